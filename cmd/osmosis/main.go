@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/priyaaank/osmosis/osmosis"
+)
+
+const usage = `Usage: osmosis <command> [options] <config files>
+
+Commands:
+  migrate    Upgrade configs to the current version of the JSON DSL
+  test       Run the examples declared by the templates in the configs
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "migrate":
+		err = migrate(os.Args[2:], os.Stdout, os.Stderr)
+	case "test":
+		err = test(os.Args[2:], os.Stdout, os.Stderr)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+//migrate prints the upgraded version of each config, or writes it back to the file with -w. The changes made are reported on
+//stderr. With -check nothing is written and an error is returned when any config needs to be upgraded.
+func migrate(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	write := flags.Bool("w", false, "write the upgraded config back to the file instead of printing it")
+	check := flags.Bool("check", false, "only report configs that need to be upgraded")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return fmt.Errorf("ERROR: No config files given to migrate")
+	}
+
+	outdated := make([]string, 0)
+
+	for _, name := range flags.Args() {
+		config, err := os.ReadFile(name)
+
+		if err != nil {
+			return fmt.Errorf("ERROR: Could not read config file %s. Error is %s", name, err.Error())
+		}
+
+		migrated, report, err := osmosis.MigrateConfig(bytes.NewReader(config))
+
+		if err != nil {
+			return fmt.Errorf("ERROR: Could not migrate config file %s. Error is %s", name, err.Error())
+		}
+
+		if report.FromVersion == report.ToVersion {
+			fmt.Fprintf(stderr, "%s: already at version %d\n", name, report.ToVersion)
+		} else {
+			outdated = append(outdated, name)
+			fmt.Fprintf(stderr, "%s: upgraded from version %d to %d\n", name, report.FromVersion, report.ToVersion)
+		}
+
+		for _, change := range report.Changes {
+			fmt.Fprintf(stderr, "  %s\n", change)
+		}
+
+		if *check {
+			continue
+		}
+
+		if !*write {
+			stdout.Write(migrated)
+			continue
+		}
+
+		if extension := strings.ToLower(filepath.Ext(name)); extension != ".json" {
+			return fmt.Errorf("ERROR: Config file %s can not be rewritten as JSON in place. Print the upgraded config instead", name)
+		}
+
+		if err := os.WriteFile(name, migrated, 0644); err != nil {
+			return fmt.Errorf("ERROR: Could not write config file %s. Error is %s", name, err.Error())
+		}
+	}
+
+	if *check && len(outdated) > 0 {
+		return fmt.Errorf("ERROR: Config files %s need to be upgraded to version %d", strings.Join(outdated, ", "), osmosis.CurrentConfigVersion)
+	}
+
+	return nil
+}
+
+//test loads the configs together and runs the examples of their templates, printing a line for each example and the attributes
+//that did not match. Example files are looked up relative to -dir, which defaults to the directory of the first config.
+func test(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dir := flags.String("dir", "", "directory that example file paths are relative to")
+	verbose := flags.Bool("v", false, "also list the examples that passed")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return fmt.Errorf("ERROR: No config files given to test")
+	}
+
+	if *dir == "" {
+		*dir = filepath.Dir(flags.Arg(0))
+	}
+
+	templates, err := osmosis.LoadConfigFiles(flags.Args()...)
+
+	if err != nil {
+		return err
+	}
+
+	report := templates.RunExamples(*dir)
+
+	for _, result := range report.Results {
+		if result.Passed() {
+			if *verbose {
+				fmt.Fprintf(stdout, "PASS %s %s\n", result.TemplateName, result.Example)
+			}
+			continue
+		}
+
+		fmt.Fprintf(stdout, "FAIL %s %s\n", result.TemplateName, result.Example)
+
+		if result.Err != nil {
+			fmt.Fprintf(stdout, "  %s\n", result.Err.Error())
+		}
+
+		for _, diff := range result.Diffs {
+			fmt.Fprintf(stdout, "  %s\n", diff.String())
+		}
+	}
+
+	fmt.Fprintf(stdout, "%d examples, %d passed, %d failed\n", len(report.Results), len(report.Results)-report.Failed(), report.Failed())
+
+	if !report.Passed() {
+		return fmt.Errorf("ERROR: %d examples failed", report.Failed())
+	}
+
+	return nil
+}
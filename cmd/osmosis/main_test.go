@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestThatMigrateRewritesConfigFilesInPlace(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "vendors.json")
+	newer := filepath.Join(dir, "newer.json")
+	os.WriteFile(name, []byte(`{"templates": [{"templateName": "Ola", "matchers": {"matcherType": "regexMatcher", "regexExpression": "ANI\\s+Tech"}}]}`), 0644)
+	os.WriteFile(newer, []byte(`{"version": 2, "templates": []}`), 0644)
+
+	var stdout, stderr bytes.Buffer
+
+	if err := migrate([]string{"-check", name}, &stdout, &stderr); err != nil {
+		t.Errorf("Did not expect -check to fail for a current config. But was %s", err.Error())
+	}
+
+	if err := migrate([]string{"-w", name}, &stdout, &stderr); err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	migrated, _ := os.ReadFile(name)
+
+	if !strings.Contains(string(migrated), "\n            \"matchers\": {") || !strings.Contains(stderr.String(), "already at version 1") {
+		t.Errorf("Expected the file to be rewritten as canonical JSON and reported as current but got %s and %s", string(migrated), stderr.String())
+	}
+
+	if err := migrate([]string{"-check", newer}, &stdout, &stderr); err == nil {
+		t.Errorf("Expected a config newer than the supported version to fail")
+	}
+}
+
+func TestThatTestReportsFailingExamples(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "vendors.json")
+	os.WriteFile(filepath.Join(dir, "ola.txt"), []byte("ANI Technologies Invoice ID CRN123"), 0644)
+	os.WriteFile(name, []byte(`{"version": 1, "templates": [{"templateName": "Ola",
+		"matchers": {"matcherType": "regexMatcher", "regexExpression": "ANI\\s+Technologies"},
+		"sections": [{"contentSelector": {"selectorType": "lineNumberSelector", "fromLine": 1, "toLine": 1},
+			"contentExtractors": [{"extractorType": "regexExtractor", "regex": "Invoice ID\s+(\w+)", "attributeName": "invoiceNumber", "groupNumber": 1}]}],
+		"examples": [
+			{"file": "ola.txt", "expected": {"invoiceNumber": "CRN123"}},
+			{"text": "ANI Technologies Invoice ID CRN456", "expected": {"invoiceNumber": "CRN123"}}
+		]}]}`), 0644)
+
+	var stdout, stderr bytes.Buffer
+	err := test([]string{name}, &stdout, &stderr)
+
+	if err == nil || !strings.Contains(stdout.String(), "FAIL Ola example 2\n  invoiceNumber: expected [CRN123] but got [CRN456]") || !strings.Contains(stdout.String(), "2 examples, 1 passed, 1 failed") {
+		t.Errorf("Expected the failing example to be reported but got %s", stdout.String())
+	}
+}
@@ -0,0 +1,9 @@
+module github.com/priyaaank/osmosis
+
+go 1.17
+
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/buger/jsonparser v1.1.1
+	gopkg.in/yaml.v2 v2.4.0
+)
@@ -7,6 +7,8 @@ import (
 	"io/ioutil"
 	"log"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/buger/jsonparser"
@@ -18,9 +20,16 @@ const (
 )
 
 type content struct {
-	OriginalText  string
-	SanitizedText string
-	Words         []string
+	OriginalText   string
+	SanitizedText  string
+	Words          []string
+	Table          *tableLayout
+	SelectionError error
+	Page           int
+	PageBreaks     []int
+	Offset         int
+	Header         string
+	Footer         string
 }
 
 //Templates is internally a map of configured templates. The key is the name of the template and the value is a template struct object.
@@ -30,49 +39,126 @@ type Templates map[string]template
 //ExtractedContent is an object which represents a key value pair. For each configured extractors an ExtractedContent can be returned.
 //AttributeName represents the configured key for the pair.
 //AttributeValue represents the extracted value for the pair.
+//Warning describes a problem an extractor ran into while extracting the value, for instance an ambiguous date it did not want to guess.
+//Block is the position, starting at 1, of the selected block the value was extracted from among the blocks its section ran on.
+//Page is the page number, starting at 1, the value was extracted from. Pages are separated by form feeds, so a text without them is
+//a single page.
 type ExtractedContent struct {
 	AttributeName  string
 	AttributeValue string
+	Warning        string
+	Block          int
+	Page           int
 }
+
+//section runs its extractors on every block its selector selects. When BlockAttributeName is configured, the attributes of each block
+//are prefixed with it and the block number, e.g. trips.2.fare, and the number of blocks is added as trips.count.
+//ID is optional and lets a template that extends another one replace this section.
+//When is an optional matcher. Blocks it does not match are skipped, so neither values nor defaults are extracted from them. A required
+//selector that fails does not fail the section when When does not match the content the section runs on.
 type section struct {
-	Selector   contentSelector
-	Extractors []contentExtractor
+	ID                 string
+	Selector           contentSelector
+	Extractors         []contentExtractor
+	BlockAttributeName string
+	When               contentMatcher
 }
 
+//template is built from a template definition. A template that extends another one inherits what it does not configure itself.
+//Abstract templates are only used as a base for other templates and never match content on their own.
+//Definition is the JSON DSL definition the template was built from, kept so that loaded templates can be written back as JSON.
+//Examples are sample documents with the attributes the template is expected to extract from them, checked by RunExamples.
 type template struct {
-	Name     string
-	Matcher  contentMatcher
-	Sections []section
+	Name               string
+	Definition         []byte
+	Abstract           bool
+	Matcher            contentMatcher
+	Sections           []section
+	ComputedAttributes []computedAttribute
+	Assertions         []assertion
+	PageFurniture      *pageFurniture
+	Examples           []templateExample
 }
 
 //LoadConfig loads the configuration from the provided io.Reader object. It expects the content to be in JSON DSL format as explained in docs.
+//The same config can also be written in YAML or TOML, the format is detected from the content.
 //Once loaded, it creates an internal struct containing all relevant information and returns a Templates object.
 //Templates object represent a set of configured templates. Method on this object can be called to parse content to match, select and extract.
+//References to the definitions block of the config are replaced by the definitions they refer to before templates are built.
+//Template names have to be unique, a template defined more than once is reported as an error.
+//Configs written for an older version of the DSL, as given by their version key, are upgraded to the current version before they are used.
+//${NAME} in the config is replaced by the environment variable or the entry of the variables block with that name.
 //An error can also be returned when config parsing encounters a problem either with minimum required configuration, syntax invalidity or other errors.
 func LoadConfig(reader io.Reader) (Templates, error) {
+	return LoadConfigWithVariables(reader, nil)
+}
+
+//LoadConfigWithVariables loads the configuration like LoadConfig. Values in the variables map take precedence over environment
+//variables and the variables block of the config when ${NAME} is replaced.
+func LoadConfigWithVariables(reader io.Reader, variables map[string]string) (Templates, error) {
 	configString, err := ioutil.ReadAll(reader)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if configString, err = configToJSON("", configString); err != nil {
+		return nil, err
+	}
+
+	if configString, _, err = migrateConfig(configString); err != nil {
+		return nil, err
+	}
+
+	if configString, err = resolveVariables(configString, variables); err != nil {
+		return nil, err
+	}
+
+	return loadConfigBytes(configString)
+}
+
+func loadConfigBytes(configString []byte) (Templates, error) {
+	var err error
+
+	if configString, err = resolveDefinitions(configString); err != nil {
+		return nil, err
+	}
+
 	templateErrors := make([]error, 0)
-	templates := map[string]template{}
+	templateNames := make([]string, 0)
+	resolver := newTemplateResolver()
 	jsonparser.ArrayEach(configString, func(value []byte, dataType jsonparser.ValueType, offset int, err error) {
 		if err != nil {
 			log.Printf("ERROR: Error extracting template block in the config at index %d. Continuing to next one", offset)
 			return
 		}
 
-		template, err := parseTemplate(value)
+		templateName, err := jsonparser.GetString(value, "templateName")
 		if err != nil {
-			templateErrors = append(templateErrors, err)
+			templateErrors = append(templateErrors, fmt.Errorf("Template name not specified in configuration"))
 			return
 		}
 
-		templates[template.Name] = template
+		if _, found := resolver.Definitions[templateName]; found {
+			templateErrors = append(templateErrors, fmt.Errorf("ERROR: Template %s is defined more than once", templateName))
+			return
+		}
+
+		templateNames = append(templateNames, templateName)
+		resolver.Definitions[templateName] = value
 	}, "templates")
 
+	templates := map[string]template{}
+	for _, templateName := range templateNames {
+		template, err := resolver.resolve(templateName)
+		if err != nil {
+			templateErrors = append(templateErrors, err)
+			continue
+		}
+
+		templates[template.Name] = template
+	}
+
 	if len(templateErrors) > 0 {
 		return nil, templateErrors[0]
 	}
@@ -82,10 +168,14 @@ func LoadConfig(reader io.Reader) (Templates, error) {
 
 //ParseText takes in a io.Reader object that can provide the content that needs to be matched across templates and then extracted from.
 //It sequentially runs matchers from all templates configured in system. Once a template matches, it applies the selectors and extractors
-//to extract the key value pairs. These key-value pairs are returned as a slice of ExtractedContent.
+//to extract the key value pairs. Computed attributes of the template are evaluated after all its sections have run.
+//When a template strips page furniture, repeated headers and footers are removed before its matcher runs.
+//These key-value pairs are returned as a slice of ExtractedContent.
 //[]ExtractedContent represents a slice of all key-value pairs
-//This method can also return error if there is a problem while parsing the content with the matched template or when a matching template
-//is not found.
+//This method can also return error if there is a problem while parsing the content with the matched template, when a selector marked as
+//required does not find its markers or when a matching template is not found.
+//When a required selector of a template fails, the key-value pairs of the other matching templates are still returned along with the error.
+//When assertions configured on a matched template fail, all key-value pairs are returned along with an *AssertionError.
 func (t *Templates) ParseText(docReader io.Reader) ([]ExtractedContent, error) {
 
 	docContent, err := ioutil.ReadAll(docReader)
@@ -95,30 +185,68 @@ func (t *Templates) ParseText(docReader io.Reader) ([]ExtractedContent, error) {
 	}
 
 	matchingKeyValues := make([]ExtractedContent, 0)
+	assertionFailures := make([]AssertionFailure, 0)
+	selectionErrors := make([]string, 0)
 	templateMap := map[string]template(*t)
 	contentToMatch := content{OriginalText: string(docContent)}
 	contentToMatch.prepare()
 
 	for _, template := range templateMap {
-		if !template.Matcher(contentToMatch) {
+		if template.Abstract {
 			continue
 		}
 
-		for _, section := range template.Sections {
-			selectedContent := section.Selector(contentToMatch)
+		templateKeyValues, templateFailures, matched, err := template.parse(contentToMatch)
 
-			for _, extractor := range section.Extractors {
-				matchingKeyValues = append(matchingKeyValues, extractor(selectedContent))
-			}
+		if err != nil {
+			selectionErrors = append(selectionErrors, err.Error())
+			continue
+		}
 
+		if matched {
+			matchingKeyValues = append(matchingKeyValues, templateKeyValues...)
+			assertionFailures = append(assertionFailures, templateFailures...)
 		}
+	}
+
+	if len(selectionErrors) > 0 {
+		sort.Strings(selectionErrors)
+		return matchingKeyValues, fmt.Errorf("%s", strings.Join(selectionErrors, "; "))
+	}
 
+	if len(assertionFailures) > 0 {
+		return matchingKeyValues, &AssertionError{Failures: assertionFailures}
 	}
 
 	return matchingKeyValues, nil
 }
 
-func parseTemplate(templateDef []byte) (template, error) {
+//parse runs the sections and computed attributes of the template on the content, if the template matches it, and checks the
+//assertions of the template against the extracted attributes
+func (t template) parse(c content) ([]ExtractedContent, []AssertionFailure, bool, error) {
+	templateContent := t.PageFurniture.strip(c)
+
+	if !t.Matcher(templateContent) {
+		return nil, nil, false, nil
+	}
+
+	templateKeyValues := make([]ExtractedContent, 0)
+
+	for _, section := range t.Sections {
+		sectionKeyValues, err := section.extract(templateContent)
+
+		if err != nil {
+			return nil, nil, true, fmt.Errorf("ERROR: Could not select content for template %s. Error is %s", t.Name, err.Error())
+		}
+
+		templateKeyValues = append(templateKeyValues, sectionKeyValues...)
+	}
+
+	templateKeyValues = append(templateKeyValues, computeAttributes(t.ComputedAttributes, templateKeyValues)...)
+	return templateKeyValues, checkAssertions(t.Name, t.Assertions, templateKeyValues), true, nil
+}
+
+func parseTemplate(templateDef []byte, baseTemplate *template) (template, error) {
 	var templateName string
 	var err error
 	var newTemplate template
@@ -128,11 +256,9 @@ func parseTemplate(templateDef []byte) (template, error) {
 		return newTemplate, fmt.Errorf("Template name not specified in configuration")
 	}
 
+	abstract, _ := jsonparser.GetBoolean(templateDef, "abstract")
 	matcherDef, _, _, err := jsonparser.Get(templateDef, "matchers")
-
-	if err != nil {
-		return newTemplate, fmt.Errorf("Matcher block is not specified for template %s. At least one matcher is required for each template", templateName)
-	}
+	hasMatcher := err == nil
 
 	sections := make([]section, 0)
 	jsonparser.ArrayEach(templateDef, func(section []byte, dataType jsonparser.ValueType, offset int, err error) {
@@ -156,15 +282,59 @@ func parseTemplate(templateDef []byte) (template, error) {
 		return newTemplate, sectionErrors[0]
 	}
 
-	matcher, err := classifyAndBuildMatcher(matcherDef)
+	var matcher contentMatcher
+
+	if hasMatcher {
+		if matcher, err = classifyAndBuildMatcher(matcherDef); err != nil {
+			return newTemplate, err
+		}
+	}
+
+	computedAttributes, err := buildComputedAttributes(templateDef)
 
 	if err != nil {
-		return newTemplate, err
+		return newTemplate, fmt.Errorf("ERROR: Could not build computed attributes for template %s. Error is %s", templateName, err.Error())
+	}
+
+	assertions, err := buildAssertions(templateDef)
+
+	if err != nil {
+		return newTemplate, fmt.Errorf("ERROR: Could not build assertions for template %s. Error is %s", templateName, err.Error())
+	}
+
+	pageFurniture, err := buildPageFurniture(templateDef)
+
+	if err != nil {
+		return newTemplate, fmt.Errorf("ERROR: Could not build page furniture for template %s. Error is %s", templateName, err.Error())
+	}
+
+	examples, err := buildExamples(templateDef)
+
+	if err != nil {
+		return newTemplate, fmt.Errorf("ERROR: Could not build examples for template %s. Error is %s", templateName, err.Error())
+	}
+
+	if abstract && len(examples) > 0 {
+		return newTemplate, fmt.Errorf("ERROR: Abstract template %s can not have examples as it never matches", templateName)
 	}
 
 	newTemplate.Name = templateName
+	newTemplate.Definition = append([]byte{}, templateDef...)
+	newTemplate.Abstract = abstract
 	newTemplate.Matcher = matcher
 	newTemplate.Sections = sections
+	newTemplate.ComputedAttributes = computedAttributes
+	newTemplate.Assertions = assertions
+	newTemplate.PageFurniture = pageFurniture
+	newTemplate.Examples = examples
+
+	if baseTemplate != nil {
+		newTemplate = newTemplate.inherit(*baseTemplate, hasMatcher, pageFurniture != nil)
+	}
+
+	if newTemplate.Matcher == nil && !newTemplate.Abstract {
+		return newTemplate, fmt.Errorf("Matcher block is not specified for template %s. At least one matcher is required for each template", templateName)
+	}
 
 	return newTemplate, nil
 }
@@ -186,6 +356,7 @@ func (c *content) prepare() error {
 	c.SanitizedText = splCharRegex.ReplaceAllString(c.SanitizedText, "")
 	c.SanitizedText = strings.TrimSpace(c.SanitizedText)
 	c.Words = strings.Split(c.SanitizedText, " ")
+	c.PageBreaks = pageBreaks(c.OriginalText, defaultPageDelimiter)
 
 	return nil
 }
@@ -224,8 +395,61 @@ func buildSection(value []byte) (section, error) {
 		return section{}, sectionError
 	}
 
+	id, _ := jsonparser.GetString(value, "id")
+	blockAttributeName, _ := jsonparser.GetString(value, "blockAttributeName")
+	var when contentMatcher
+
+	if whenDef, _, _, err := jsonparser.Get(value, "when"); err == nil {
+		if when, err = classifyAndBuildMatcher(whenDef); err != nil {
+			return section{}, fmt.Errorf("ERROR: Could not create when matcher for the section. Error is %s", err.Error())
+		}
+	}
+
 	return section{
-		Selector:   contentSelector,
-		Extractors: extractors,
+		ID:                 id,
+		Selector:           contentSelector,
+		Extractors:         extractors,
+		BlockAttributeName: blockAttributeName,
+		When:               when,
 	}, nil
 }
+
+func (s section) extract(c content) ([]ExtractedContent, error) {
+	sectionKeyValues := make([]ExtractedContent, 0)
+	blockCount := 0
+
+	for _, selectedContent := range s.Selector(c) {
+		if selectedContent.SelectionError != nil {
+			if s.When != nil && !s.When(c) {
+				continue
+			}
+			return nil, selectedContent.SelectionError
+		}
+
+		if s.When != nil && !s.When(selectedContent) {
+			continue
+		}
+
+		blockCount++
+		selectedContent = c.locate(selectedContent)
+
+		for _, extractor := range s.Extractors {
+			for _, extracted := range extractor(selectedContent) {
+				extracted.Block = blockCount
+				if extracted.Page == 0 {
+					extracted.Page = selectedContent.Page
+				}
+				if s.BlockAttributeName != "" {
+					extracted.AttributeName = fmt.Sprintf("%s.%d.%s", s.BlockAttributeName, blockCount, extracted.AttributeName)
+				}
+				sectionKeyValues = append(sectionKeyValues, extracted)
+			}
+		}
+	}
+
+	if s.BlockAttributeName != "" {
+		sectionKeyValues = append(sectionKeyValues, ExtractedContent{AttributeName: s.BlockAttributeName + ".count", AttributeValue: strconv.Itoa(blockCount)})
+	}
+
+	return sectionKeyValues, nil
+}
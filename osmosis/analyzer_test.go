@@ -157,3 +157,206 @@ func TestThatKeyValueMapIsReturnedForTextWhenMatchingTemplateIsPresent(t *testin
 	}
 
 }
+
+func TestThatSectionRunsExtractorsOnEverySelectedBlock(t *testing.T) {
+	statement := `Uber Trip Statement
+Trip 1
+Fare: 100.0
+Trip 2
+Fare: 150.0
+Trip 3
+Fare: 90.5
+`
+	templates, err := LoadConfig(strings.NewReader(`{
+		"templates": [
+			{
+				"templateName": "Uber statement",
+				"matchers": {
+					"matcherType": "oneWordMatcher",
+					"words": "Uber Trip Statement"
+				},
+				"sections" : [
+					{
+						"blockAttributeName": "trips",
+						"contentSelector": {
+							"selectorType": "textBlockSelector",
+							"fromRegex" : "(?m)^Trip \d+",
+							"toRegex": "(?m)^Trip \d+",
+							"all": true
+						},
+						"contentExtractors": [
+							{
+								"extractorType": "regexExtractor",
+								"regex": "Fare:\s+([\d.]+)",
+								"attributeName": "fare",
+								"defaultValue":"NA",
+								"groupNumber":1
+							}
+						]
+					}
+				],
+				"computedAttributes": [
+					{
+						"attributeName": "totalFare",
+						"expression": "sumOf(\"trips.*.fare\")"
+					}
+				]
+			}
+		]
+	}`))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader(statement))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be raised but was %s", err.Error())
+	}
+
+	values := attributeValues(keyValuePairs)
+
+	if values["trips.1.fare"] != "100.0" || values["trips.2.fare"] != "150.0" || values["trips.3.fare"] != "90.5" {
+		t.Errorf("Expected fare of every trip to be extracted but got %v", values)
+	}
+
+	if values["trips.count"] != "3" || values["totalFare"] != "340.5" {
+		t.Errorf("Expected trip count 3 and total fare 340.5 but got %s and %s", values["trips.count"], values["totalFare"])
+	}
+
+	if keyValuePairs[1].Block != 2 {
+		t.Errorf("Expected second fare to come from block 2 but was %d", keyValuePairs[1].Block)
+	}
+}
+
+func TestThatSectionIsSkippedWhenItsWhenMatcherDoesNotMatch(t *testing.T) {
+	config := `{
+		"templates": [
+			{
+				"templateName": "Uber statement",
+				"matchers": {
+					"matcherType": "oneWordMatcher",
+					"words": "Uber Trip Statement"
+				},
+				"sections" : [
+					{
+						"when": {
+							"matcherType": "oneWordMatcher",
+							"words": "Toll"
+						},
+						"contentSelector": {
+							"selectorType": "textBlockSelector"
+						},
+						"contentExtractors": [
+							{
+								"extractorType": "regexExtractor",
+								"regex": "Toll:\s+([\d.]+)",
+								"attributeName": "toll",
+								"defaultValue":"0.0",
+								"groupNumber":1
+							}
+						]
+					},
+					{
+						"blockAttributeName": "promos",
+						"when": {
+							"matcherType": "oneWordMatcher",
+							"words": "Promo"
+						},
+						"contentSelector": {
+							"selectorType": "paragraphSelector"
+						},
+						"contentExtractors": [
+							{
+								"extractorType": "regexExtractor",
+								"regex": "Promo\s+(\w+)",
+								"attributeName": "code",
+								"defaultValue":"NA",
+								"groupNumber":1
+							}
+						]
+					}
+				]
+			}
+		]
+	}`
+	templates, err := LoadConfig(strings.NewReader(config))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader("Uber Trip Statement\nFare: 100.0\n\nPromo RIDE50 applied\n\nThanks"))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be raised but was %s", err.Error())
+	}
+
+	values := attributeValues(keyValuePairs)
+
+	if _, found := values["toll"]; found {
+		t.Errorf("Did not expect toll or its default value to be extracted but got %v", values)
+	}
+
+	if len(values) != 2 || values["promos.1.code"] != "RIDE50" || values["promos.count"] != "1" {
+		t.Errorf("Expected only the paragraph with a promo to be extracted but got %v", values)
+	}
+}
+
+func TestThatPairsOfOtherTemplatesAreReturnedWhenARequiredSelectorFails(t *testing.T) {
+	config := `{"templates": [
+		{
+			"templateName": "Ola",
+			"matchers": {"matcherType": "oneWordMatcher", "words": "ANI Technologies"},
+			"sections": [{
+				"contentSelector": {"selectorType": "lineNumberSelector", "fromLine": 1, "toLine": 1},
+				"contentExtractors": [{"extractorType": "regexExtractor", "regex": "Invoice ID\s+(\w+)", "attributeName": "invoiceNumber", "groupNumber": 1}]
+			}]
+		},
+		{
+			"templateName": "Strict",
+			"matchers": {"matcherType": "oneWordMatcher", "words": "ANI Technologies"},
+			"sections": [{
+				"contentSelector": {"selectorType": "textBlockSelector", "fromText": "NotPresent", "toText": "Description", "required": true},
+				"contentExtractors": [{"extractorType": "regexExtractor", "regex": "(\w+)", "attributeName": "word", "groupNumber": 1}]
+			}]
+		}
+	]}`
+	templates, _ := LoadConfig(strings.NewReader(config))
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader(contentString))
+
+	if err == nil || !strings.Contains(err.Error(), "Strict") {
+		t.Errorf("Expected an error for the required selector of the Strict template but got %v", err)
+	}
+
+	if values := attributeValues(keyValuePairs); len(keyValuePairs) != 1 || values["invoiceNumber"] != "1IE88NHTQ55547" {
+		t.Errorf("Expected the pairs of the Ola template to be returned but got %v", keyValuePairs)
+	}
+}
+
+func TestThatRequiredSelectorDoesNotFailWhenItsSectionDoesNotApply(t *testing.T) {
+	config := `{"templates": [{
+		"templateName": "Uber statement",
+		"matchers": {"matcherType": "oneWordMatcher", "words": "Uber Trip Statement"},
+		"sections": [{
+			"when": {"matcherType": "oneWordMatcher", "words": "Toll"},
+			"contentSelector": {"selectorType": "textBlockSelector", "fromText": "Toll", "toText": "Thanks", "required": true},
+			"contentExtractors": [{"extractorType": "regexExtractor", "regex": "Toll:\s+([\d.]+)", "attributeName": "toll", "groupNumber": 1}]
+		}]
+	}]}`
+	templates, _ := LoadConfig(strings.NewReader(config))
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader("Uber Trip Statement\nFare: 100.0\n\nThanks"))
+
+	if err != nil || len(keyValuePairs) != 0 {
+		t.Errorf("Expected the section to be skipped but got %v and %v", keyValuePairs, err)
+	}
+
+	keyValuePairs, err = templates.ParseText(strings.NewReader("Uber Trip Statement\nToll: 20.0\n\nRegards"))
+
+	if err == nil {
+		t.Errorf("Expected the required selector to fail when the section applies but got %v", keyValuePairs)
+	}
+}
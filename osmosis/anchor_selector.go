@@ -0,0 +1,122 @@
+package osmosis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/buger/jsonparser"
+)
+
+type anchorSelector struct {
+	AnchorText    string
+	AnchorRegex   string
+	AnchorLine    int64
+	Occurrence    int64
+	LinesBefore   int64
+	LinesAfter    int64
+	IncludeAnchor bool
+}
+
+func getAnchorSelector(value []byte) anchorSelector {
+	anchorText, _ := jsonparser.GetString(value, "anchorText")
+	anchorRegex, _, _, _ := jsonparser.Get(value, "anchorRegex")
+	anchorLine, _ := jsonparser.GetInt(value, "anchorLine")
+	linesBefore, _ := jsonparser.GetInt(value, "linesBefore")
+	linesAfter, _ := jsonparser.GetInt(value, "linesAfter")
+	occurrence, err := jsonparser.GetInt(value, "occurrence")
+
+	if err != nil {
+		occurrence = 1
+	}
+
+	includeAnchor, err := jsonparser.GetBoolean(value, "includeAnchor")
+
+	if err != nil {
+		includeAnchor = true
+	}
+
+	return anchorSelector{
+		AnchorText:    anchorText,
+		AnchorRegex:   string(anchorRegex),
+		AnchorLine:    anchorLine,
+		Occurrence:    occurrence,
+		LinesBefore:   linesBefore,
+		LinesAfter:    linesAfter,
+		IncludeAnchor: includeAnchor,
+	}
+}
+
+func (as anchorSelector) asContentSelector() (contentSelector, error) {
+	var compiledRegex *regexp.Regexp
+	var err error
+
+	if as.AnchorText == "" && as.AnchorRegex == "" && as.AnchorLine == 0 {
+		return nil, fmt.Errorf("ERROR: Anchor selector requires one of anchorText, anchorRegex or anchorLine")
+	}
+
+	if as.Occurrence == 0 || as.LinesBefore < 0 || as.LinesAfter < 0 {
+		return nil, fmt.Errorf("ERROR: Anchor selector occurrence should not be 0 and linesBefore, linesAfter should not be negative")
+	}
+
+	if as.AnchorRegex != "" {
+		if compiledRegex, err = regexp.Compile(as.AnchorRegex); err != nil {
+			return nil, fmt.Errorf("ERROR: Regex %s for anchor selector did not compile. Error is %s", as.AnchorRegex, err.Error())
+		}
+	}
+
+	return func(c content) []content {
+		lines := strings.Split(c.OriginalText, "\n")
+		anchorIndex := as.findAnchor(lines, compiledRegex)
+
+		if anchorIndex == -1 {
+			return []content{{OriginalText: ""}}
+		}
+
+		fromIndex := maxInt(anchorIndex-int(as.LinesBefore), 0)
+		toIndex := minInt(anchorIndex+int(as.LinesAfter)+1, len(lines))
+		selectedLines := append([]string{}, lines[fromIndex:anchorIndex]...)
+		offset := lineOffset(lines, fromIndex)
+
+		if as.IncludeAnchor {
+			selectedLines = append(selectedLines, lines[anchorIndex])
+		} else if fromIndex == anchorIndex {
+			offset = lineOffset(lines, anchorIndex+1)
+		}
+
+		return []content{newSelectedContentAt(strings.Join(append(selectedLines, lines[anchorIndex+1:toIndex]...), "\n"), offset)}
+	}, nil
+}
+
+//findAnchor returns the index of the anchor line. Negative line numbers and occurrences are counted from the end of the content.
+func (as anchorSelector) findAnchor(lines []string, compiledRegex *regexp.Regexp) int {
+	if as.AnchorText == "" && compiledRegex == nil {
+		index := int(as.AnchorLine) - 1
+		if as.AnchorLine < 0 {
+			index = len(lines) + int(as.AnchorLine)
+		}
+		if index < 0 || index >= len(lines) {
+			return -1
+		}
+		return index
+	}
+
+	matchingIndexes := make([]int, 0)
+
+	for index, line := range lines {
+		if (as.AnchorText != "" && strings.Contains(line, as.AnchorText)) || (compiledRegex != nil && compiledRegex.MatchString(line)) {
+			matchingIndexes = append(matchingIndexes, index)
+		}
+	}
+
+	occurrence := int(as.Occurrence) - 1
+	if as.Occurrence < 0 {
+		occurrence = len(matchingIndexes) + int(as.Occurrence)
+	}
+
+	if occurrence < 0 || occurrence >= len(matchingIndexes) {
+		return -1
+	}
+
+	return matchingIndexes[occurrence]
+}
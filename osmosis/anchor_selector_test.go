@@ -0,0 +1,83 @@
+package osmosis
+
+import (
+	"strings"
+	"testing"
+)
+
+var statementText = `Uber Trip Statement
+Trip 1
+Fare: 100.0
+Toll: 20.0
+Trip 2
+Fare: 150.0
+Generated on 12 Jun 2018
+Page 1 of 1`
+
+func TestThatAnchorSelectorSelectsLinesAroundNthOccurrence(t *testing.T) {
+	c := content{OriginalText: statementText}
+	c.prepare()
+
+	selector, _ := classifyAndBuildSelector([]byte(`{
+		"selectorType": "anchorSelector",
+		"anchorText": "Fare:",
+		"occurrence": 2,
+		"linesBefore": 1,
+		"linesAfter": 1
+	}`))
+	selected := selector(c)[0].OriginalText
+
+	if strings.Compare(selected, "Trip 2\nFare: 150.0\nGenerated on 12 Jun 2018") != 0 {
+		t.Errorf("Expected lines around second fare but got [%s]", selected)
+	}
+}
+
+func TestThatAnchorSelectorCountsNegativeOccurrenceFromTheEnd(t *testing.T) {
+	c := content{OriginalText: statementText}
+	c.prepare()
+
+	selector, _ := classifyAndBuildSelector([]byte(`{
+		"selectorType": "anchorSelector",
+		"anchorRegex": "^Trip\s+\d+$",
+		"occurrence": -2,
+		"linesAfter": 2,
+		"includeAnchor": false
+	}`))
+	selected := selector(c)[0].OriginalText
+
+	if strings.Compare(selected, "Fare: 100.0\nToll: 20.0") != 0 {
+		t.Errorf("Expected lines after first trip heading but got [%s]", selected)
+	}
+}
+
+func TestThatAnchorSelectorCountsNegativeLineNumbersFromTheEnd(t *testing.T) {
+	c := content{OriginalText: statementText}
+	c.prepare()
+
+	selector, _ := classifyAndBuildSelector([]byte(`{
+		"selectorType": "anchorSelector",
+		"anchorLine": -2,
+		"linesAfter": 5
+	}`))
+	selected := selector(c)[0].OriginalText
+
+	if strings.Compare(selected, "Generated on 12 Jun 2018\nPage 1 of 1") != 0 {
+		t.Errorf("Expected last two lines but got [%s]", selected)
+	}
+}
+
+func TestThatAnchorSelectorReturnsEmptyContentWhenAnchorIsMissing(t *testing.T) {
+	c := content{OriginalText: statementText}
+	c.prepare()
+
+	selector, _ := classifyAndBuildSelector([]byte(`{
+		"selectorType": "anchorSelector",
+		"anchorText": "Surge",
+		"linesAfter": 2
+	}`))
+	selected := selector(c)[0].OriginalText
+
+	if selected != "" {
+		t.Errorf("Expected empty selection but got [%s]", selected)
+	}
+}
@@ -0,0 +1,135 @@
+package osmosis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/buger/jsonparser"
+)
+
+type assertion struct {
+	Name       string
+	Expression string
+	Tolerance  float64
+	Evaluate   expression
+}
+
+//AssertionFailure describes a configured assertion that did not hold for the attributes extracted by a template.
+//Message explains whether the rule evaluated to false or could not be evaluated at all.
+type AssertionFailure struct {
+	TemplateName string
+	Name         string
+	Expression   string
+	Message      string
+}
+
+//AssertionError is returned by ParseText along with the extracted content when one or more template assertions fail.
+//The extracted content is still returned in full so the caller can decide how to treat the failures.
+type AssertionError struct {
+	Failures []AssertionFailure
+}
+
+func (ae *AssertionError) Error() string {
+	messages := make([]string, 0, len(ae.Failures))
+
+	for _, failure := range ae.Failures {
+		messages = append(messages, fmt.Sprintf("%s/%s: %s", failure.TemplateName, failure.Name, failure.Message))
+	}
+
+	return fmt.Sprintf("ERROR: %d assertion(s) failed. %s", len(ae.Failures), strings.Join(messages, "; "))
+}
+
+func buildAssertions(templateDef []byte) ([]assertion, error) {
+	assertions := make([]assertion, 0)
+	defaultTolerance, err := jsonparser.GetFloat(templateDef, "assertionTolerance")
+
+	if err != nil {
+		defaultTolerance = 0
+	}
+
+	var parseError error
+
+	jsonparser.ArrayEach(templateDef, func(value []byte, dataType jsonparser.ValueType, offset int, err error) {
+		if err != nil {
+			parseError = err
+			return
+		}
+
+		parsedAssertion, err := getAssertion(value, defaultTolerance)
+
+		if err != nil {
+			parseError = err
+			return
+		}
+
+		assertions = append(assertions, parsedAssertion)
+	}, "assertions")
+
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	return assertions, nil
+}
+
+func getAssertion(value []byte, defaultTolerance float64) (assertion, error) {
+	expressionText, err := jsonparser.GetString(value, "expression")
+
+	if err != nil {
+		return assertion{}, fmt.Errorf("ERROR: Assertion does not specify an expression")
+	}
+
+	name, err := jsonparser.GetString(value, "name")
+
+	if err != nil {
+		name = expressionText
+	}
+
+	tolerance, err := jsonparser.GetFloat(value, "tolerance")
+
+	if err != nil {
+		tolerance = defaultTolerance
+	}
+
+	compiledExpression, err := compileExpression(expressionText)
+
+	if err != nil {
+		return assertion{}, fmt.Errorf("ERROR: Could not compile expression for assertion %s. Error is %s", name, err.Error())
+	}
+
+	return assertion{
+		Name:       name,
+		Expression: expressionText,
+		Tolerance:  tolerance,
+		Evaluate:   compiledExpression,
+	}, nil
+}
+
+//checkAssertions evaluates each assertion over the extracted attributes and returns the ones that did not hold
+func checkAssertions(templateName string, assertions []assertion, extracted []ExtractedContent) []AssertionFailure {
+	failures := make([]AssertionFailure, 0)
+	attributes := attributeValues(extracted)
+
+	for _, rule := range assertions {
+		failure := AssertionFailure{
+			TemplateName: templateName,
+			Name:         rule.Name,
+			Expression:   rule.Expression,
+		}
+
+		result, err := rule.Evaluate(expressionScope{Attributes: attributes, Tolerance: rule.Tolerance})
+
+		if err != nil {
+			failure.Message = fmt.Sprintf("could not be evaluated. Error is %s", err.Error())
+			failures = append(failures, failure)
+			continue
+		}
+
+		if isTrue, err := asBool(result); err != nil || !isTrue {
+			failure.Message = fmt.Sprintf("evaluated to %s", formatValue(result))
+			failures = append(failures, failure)
+		}
+	}
+
+	return failures
+}
@@ -0,0 +1,95 @@
+package osmosis
+
+import (
+	"strings"
+	"testing"
+)
+
+var receiptText = `Item Amount 1: 100.00
+Item Amount 2: 40.00
+Subtotal: 140.00
+CGST: 3.50
+SGST: 3.50
+GST: 7.00
+Total: 147.01
+`
+
+var assertionConfig = `{
+	"templates": [
+		{
+			"templateName": "Receipt",
+			"matchers": {
+				"matcherType": "oneWordMatcher",
+				"words": "Subtotal"
+			},
+			"assertionTolerance": 0.05,
+			"sections" : [
+				{
+					"contentSelector": {
+						"selectorType": "lineNumberSelector"
+					},
+					"contentExtractors": [
+						{ "extractorType": "regexExtractor", "regex": "Amount 1:\s+([\d.]+)", "attributeName": "itemAmount1", "groupNumber": 1 },
+						{ "extractorType": "regexExtractor", "regex": "Amount 2:\s+([\d.]+)", "attributeName": "itemAmount2", "groupNumber": 1 },
+						{ "extractorType": "regexExtractor", "regex": "Subtotal:\s+([\d.]+)", "attributeName": "subtotal", "groupNumber": 1 },
+						{ "extractorType": "regexExtractor", "regex": "CGST:\s+([\d.]+)", "attributeName": "cgst", "groupNumber": 1 },
+						{ "extractorType": "regexExtractor", "regex": "SGST:\s+([\d.]+)", "attributeName": "sgst", "groupNumber": 1 },
+						{ "extractorType": "regexExtractor", "regex": "\nGST:\s+([\d.]+)", "attributeName": "gst", "groupNumber": 1 },
+						{ "extractorType": "regexExtractor", "regex": "Total:\s+([\d.]+)", "attributeName": "total", "groupNumber": 1 }
+					]
+				}
+			],
+			"assertions": [
+				{ "name": "lineItemsAddUp", "expression": "sumOf(\"itemAmount*\") == subtotal" },
+				{ "name": "gstSplit", "expression": "gst == cgst + sgst" },
+				{ "name": "totalIsExact", "expression": "subtotal + gst == total", "tolerance": 0 },
+				{ "name": "hasDiscount", "expression": "discount > 0" }
+			]
+		}
+	]
+}
+`
+
+func TestThatFailedAssertionsAreReportedWithExtractedContent(t *testing.T) {
+	templates, err := LoadConfig(strings.NewReader(assertionConfig))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader(receiptText))
+
+	if len(keyValuePairs) != 7 {
+		t.Errorf("Expected all 7 extracted key value pairs to be returned but got %d", len(keyValuePairs))
+	}
+
+	assertionErr, ok := err.(*AssertionError)
+
+	if !ok {
+		t.Fatalf("Expected an assertion error to be returned but was %v", err)
+	}
+
+	if len(assertionErr.Failures) != 2 {
+		t.Fatalf("Expected 2 assertion failures but got %v", assertionErr.Failures)
+	}
+
+	if assertionErr.Failures[0].Name != "totalIsExact" || assertionErr.Failures[0].TemplateName != "Receipt" {
+		t.Errorf("Expected totalIsExact to fail without tolerance but got %v", assertionErr.Failures[0])
+	}
+
+	if assertionErr.Failures[1].Name != "hasDiscount" || !strings.Contains(assertionErr.Failures[1].Message, "discount") {
+		t.Errorf("Expected hasDiscount to fail as the attribute is missing but got %v", assertionErr.Failures[1])
+	}
+}
+
+func TestThatAssertionsWithinToleranceDoNotReturnError(t *testing.T) {
+	config := strings.Replace(assertionConfig, `"tolerance": 0 `, `"tolerance": 0.01 `, 1)
+	config = strings.Replace(config, `{ "name": "hasDiscount", "expression": "discount > 0" }`, `{ "expression": "total > 0" }`, 1)
+	templates, _ := LoadConfig(strings.NewReader(config))
+
+	_, err := templates.ParseText(strings.NewReader(receiptText))
+
+	if err != nil {
+		t.Errorf("Did not expect assertions to fail but got %s", err.Error())
+	}
+}
@@ -0,0 +1,152 @@
+package osmosis
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/buger/jsonparser"
+)
+
+const (
+	camelCaseKeys = "camelCase"
+	snakeCaseKeys = "snakeCase"
+)
+
+type autoKeyValueExtractor struct {
+	AttributePrefix string
+	KeyStyle        string
+	MaxLabelWords   int64
+}
+
+//keyValueLayouts are tried in order on each line. Each of them captures the label and the value.
+var keyValueLayouts = []*regexp.Regexp{
+	regexp.MustCompile(`^\s*([^:=.]+?)\s*\.{3,}\s*(\S.*?)\s*$`),
+	regexp.MustCompile(`^\s*([^:=]+?)\s*[:=]\s*(\S.*?)\s*$`),
+	regexp.MustCompile(`^\s*(\S.*?\S|\S)(?:\s{2,}|\t+)(\S.*?)\s*$`),
+}
+
+//columnBreakRegex finds a wide gap followed by a label and a separator, where a line holds a further pair next to the first one
+var columnBreakRegex = regexp.MustCompile(`(?:\s{2,}|\t+)([^\s:=][^:=]*?)\s*[:=]`)
+
+var labelOnlyRegex = regexp.MustCompile(`^\s*([^:=]+?)\s*:\s*$`)
+var labelStartRegex = regexp.MustCompile(`^\pL`)
+
+func getAutoKeyValueExtractor(value []byte) autoKeyValueExtractor {
+	attributePrefix, _ := jsonparser.GetString(value, "attributePrefix")
+	keyStyle, err := jsonparser.GetString(value, "keyStyle")
+
+	if err != nil {
+		keyStyle = camelCaseKeys
+	}
+
+	maxLabelWords, err := jsonparser.GetInt(value, "maxLabelWords")
+
+	if err != nil {
+		maxLabelWords = 5
+	}
+
+	return autoKeyValueExtractor{
+		AttributePrefix: attributePrefix,
+		KeyStyle:        keyStyle,
+		MaxLabelWords:   maxLabelWords,
+	}
+}
+
+func (akve autoKeyValueExtractor) asContentExtractor() (contentExtractor, error) {
+	return func(c content) []ExtractedContent {
+		discoveredPairs := make([]ExtractedContent, 0)
+		keyCounts := map[string]int{}
+		lines := strings.Split(c.OriginalText, "\n")
+
+		addPair := func(label string, value string) {
+			key := akve.AttributePrefix + normalizeKey(label, akve.KeyStyle)
+			keyCounts[key]++
+
+			if keyCounts[key] > 1 {
+				key = key + strconv.Itoa(keyCounts[key])
+			}
+
+			discoveredPairs = append(discoveredPairs, ExtractedContent{AttributeName: key, AttributeValue: value})
+		}
+
+		for index, line := range lines {
+			if columns := akve.splitColumns(line); len(columns) > 1 {
+				for _, column := range columns {
+					if label, value, found := akve.findPair(column); found && value != "" {
+						addPair(label, value)
+					}
+				}
+				continue
+			}
+
+			label, value, found := akve.findPair(line)
+
+			if !found {
+				if match := labelOnlyRegex.FindStringSubmatch(line); match != nil && akve.isLabel(match[1]) {
+					label, value, found = match[1], strings.TrimSpace(nextNonEmptyLine(lines[index+1:])), true
+				}
+			}
+
+			if !found || value == "" || labelOnlyRegex.MatchString(value) {
+				continue
+			}
+
+			addPair(label, value)
+		}
+
+		return discoveredPairs
+	}, nil
+}
+
+func (akve autoKeyValueExtractor) findPair(line string) (string, string, bool) {
+	for _, layout := range keyValueLayouts {
+		match := layout.FindStringSubmatch(line)
+
+		if match != nil && akve.isLabel(match[1]) {
+			return match[1], match[2], true
+		}
+	}
+
+	return "", "", false
+}
+
+//splitColumns splits a line like "Invoice No: 123        Date: 12/01/2020", which holds several pairs side by side, at the wide
+//gaps in front of each further label. A line with a single pair is returned as it is.
+func (akve autoKeyValueExtractor) splitColumns(line string) []string {
+	columns := make([]string, 0)
+	start := 0
+
+	for _, match := range columnBreakRegex.FindAllStringSubmatchIndex(line, -1) {
+		previousColumn := line[start:match[0]]
+
+		if strings.ContainsAny(previousColumn, ":=") && akve.isLabel(line[match[2]:match[3]]) {
+			columns = append(columns, previousColumn)
+			start = match[0]
+		}
+	}
+
+	return append(columns, line[start:])
+}
+
+//isLabel accepts short texts that start with a letter, which keeps amounts and sentences from being read as labels
+func (akve autoKeyValueExtractor) isLabel(text string) bool {
+	words := strings.Fields(normalizeLabel(text))
+	return len(words) > 0 && int64(len(words)) <= akve.MaxLabelWords && labelStartRegex.MatchString(strings.TrimSpace(text))
+}
+
+//normalizeKey turns a label like "Phone No." into phoneNo or phone_no depending on the key style
+func normalizeKey(label string, keyStyle string) string {
+	words := strings.Fields(normalizeLabel(label))
+
+	if strings.EqualFold(keyStyle, snakeCaseKeys) {
+		return strings.Join(words, "_")
+	}
+
+	for index := 1; index < len(words); index++ {
+		letters := []rune(words[index])
+		words[index] = strings.ToUpper(string(letters[0])) + string(letters[1:])
+	}
+
+	return strings.Join(words, "")
+}
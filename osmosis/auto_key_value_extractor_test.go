@@ -0,0 +1,82 @@
+package osmosis
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestThatAutoKeyValueExtractorDiscoversPairsInAllLayouts(t *testing.T) {
+	text := "Invoice No: FM-KA-4931389\nTrip Fare ........ 140.00\nDriver Name    Ramesh K\nThis line has no pair\nName:\n\nJuhi Chawla\nTotal = 147.0"
+	extractor, err := classifyAndBuildExtractor([]byte(`{"extractorType": "autoKeyValueExtractor"}`))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	c := content{OriginalText: text}
+	c.prepare()
+	pairs := extractor(c)
+
+	expected := []ExtractedContent{
+		{AttributeName: "invoiceNo", AttributeValue: "FM-KA-4931389"},
+		{AttributeName: "tripFare", AttributeValue: "140.00"},
+		{AttributeName: "driverName", AttributeValue: "Ramesh K"},
+		{AttributeName: "name", AttributeValue: "Juhi Chawla"},
+		{AttributeName: "total", AttributeValue: "147.0"},
+	}
+
+	if len(pairs) != len(expected) {
+		t.Fatalf("Expected %d pairs but got %v", len(expected), pairs)
+	}
+
+	for index, pair := range expected {
+		if pairs[index].AttributeName != pair.AttributeName || pairs[index].AttributeValue != pair.AttributeValue {
+			t.Errorf("Expected %v at position %d but got %v", pair, index, pairs[index])
+		}
+	}
+}
+
+func TestThatAutoKeyValueExtractorPrefixesAndDeduplicatesKeys(t *testing.T) {
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "autoKeyValueExtractor", "attributePrefix": "auto.", "keyStyle": "snakeCase"}`))
+	c := content{OriginalText: "Phone No.: 8899776655\nPhone No.: 9988776655"}
+	c.prepare()
+	pairs := extractor(c)
+
+	if len(pairs) != 2 || pairs[0].AttributeName != "auto.phone_no" || pairs[1].AttributeName != "auto.phone_no2" {
+		t.Errorf("Expected prefixed snake case keys with a suffix for duplicates but got %v", pairs)
+	}
+}
+
+func TestThatAutoKeyValueExtractorBootstrapsFromUnknownReceipt(t *testing.T) {
+	receipt, _ := ioutil.ReadFile("../examples/textfiles/freshmenu_receipt.txt")
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "autoKeyValueExtractor"}`))
+	c := content{OriginalText: string(receipt)}
+	c.prepare()
+
+	values := attributeValues(extractor(c))
+
+	if values["invoiceNo"] != "FM-KA-4931389" || values["phoneNo"] != "8899776655" || values["gstIn"] != "29BBZZF8899Q0ZQ" {
+		t.Errorf("Expected invoice, phone and GST numbers to be discovered but got %v", values)
+	}
+}
+
+func TestThatAutoKeyValueExtractorSplitsTwoColumnLayouts(t *testing.T) {
+	text := "Invoice No: 123        Date: 12/01/2020\nCustomer: Juhi Chawla\tPhone = 8899776655\nTime: 10:30  Mode: Cash"
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "autoKeyValueExtractor"}`))
+
+	c := content{OriginalText: text}
+	c.prepare()
+	values := attributeValues(extractor(c))
+
+	expected := map[string]string{"invoiceNo": "123", "date": "12/01/2020", "customer": "Juhi Chawla", "phone": "8899776655", "time": "10:30", "mode": "Cash"}
+
+	if len(values) != len(expected) {
+		t.Fatalf("Expected %d pairs but got %v", len(expected), values)
+	}
+
+	for key, value := range expected {
+		if values[key] != value {
+			t.Errorf("Expected %s to be [%s] but got [%s]", key, value, values[key])
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package osmosis
+
+import (
+	"bytes"
+	"strings"
+)
+
+//Matcher is the definition of a matcher built in code. It holds the same keys as a matcher block in the JSON DSL.
+type Matcher map[string]interface{}
+
+//Selector is the definition of a selector built in code. It holds the same keys as a contentSelector block in the JSON DSL.
+type Selector map[string]interface{}
+
+//Extractor is the definition of an extractor built in code. It holds the same keys as an extractor block in the JSON DSL.
+type Extractor map[string]interface{}
+
+//TemplateBuilder builds a template in code instead of a JSON config. It produces a template definition in the JSON DSL, so a built
+//template behaves exactly like one loaded using LoadConfig and can be written back to a config file.
+type TemplateBuilder struct {
+	definition map[string]interface{}
+}
+
+//NewTemplate starts building a template with the given name
+func NewTemplate(name string) *TemplateBuilder {
+	return &TemplateBuilder{definition: map[string]interface{}{"templateName": name}}
+}
+
+//OneOf matches content that contains at least one of the words
+func OneOf(words ...string) Matcher {
+	return Matcher{"matcherType": "oneWordMatcher", "words": strings.Join(words, ",")}
+}
+
+//AllOf matches content that contains all of the words
+func AllOf(words ...string) Matcher {
+	return Matcher{"matcherType": "allWordsMatcher", "words": strings.Join(words, ",")}
+}
+
+//MatchRegex matches content whose sanitized text matches the regex
+func MatchRegex(regex string) Matcher {
+	return Matcher{"matcherType": "regexMatcher", "regexExpression": regex}
+}
+
+//And matches content that all of the matchers match
+func And(matchers ...Matcher) Matcher {
+	return conditionalMatcherDefinition("and", matchers)
+}
+
+//Or matches content that any of the matchers match
+func Or(matchers ...Matcher) Matcher {
+	return conditionalMatcherDefinition("or", matchers)
+}
+
+func conditionalMatcherDefinition(condition string, matchers []Matcher) Matcher {
+	expressions := make([]interface{}, 0, len(matchers))
+
+	for _, matcher := range matchers {
+		expressions = append(expressions, map[string]interface{}(matcher))
+	}
+
+	return Matcher{"matcherType": "conditionalMatcher", "condition": condition, "expressions": expressions}
+}
+
+//NewSelector creates a selector of any of the selector types. Its options can be set using With.
+func NewSelector(selectorType string) Selector {
+	return Selector{"selectorType": selectorType}
+}
+
+//TextBlock selects the text from fromText to toText
+func TextBlock(fromText string, toText string) Selector {
+	return NewSelector("textBlockSelector").With("fromText", fromText).With("toText", toText)
+}
+
+//Lines selects the lines from fromLine to toLine, both inclusive and numbered from 1
+func Lines(fromLine int, toLine int) Selector {
+	return NewSelector("lineNumberSelector").With("fromLine", fromLine).With("toLine", toLine)
+}
+
+//RegexSelect selects the group of the first match of the regex
+func RegexSelect(regex string, groupNumber int) Selector {
+	return NewSelector("regexSelector").With("regex", regex).With("groupNumber", groupNumber)
+}
+
+//With sets an option of the selector and returns the selector
+func (s Selector) With(key string, value interface{}) Selector {
+	s[key] = value
+	return s
+}
+
+//Then nests a selector that selects from the content selected by this selector
+func (s Selector) Then(nested Selector) Selector {
+	s["contentSelector"] = map[string]interface{}(nested)
+	return s
+}
+
+//NewExtractor creates an extractor of any of the extractor types for the attribute. Its options can be set using With.
+func NewExtractor(extractorType string, attributeName string) Extractor {
+	return Extractor{"extractorType": extractorType, "attributeName": attributeName}
+}
+
+//RegexExtract extracts the group of the first match of the regex as the value of the attribute
+func RegexExtract(attributeName string, regex string, groupNumber int) Extractor {
+	return NewExtractor("regexExtractor", attributeName).With("regex", regex).With("groupNumber", groupNumber)
+}
+
+//With sets an option of the extractor and returns the extractor
+func (e Extractor) With(key string, value interface{}) Extractor {
+	e[key] = value
+	return e
+}
+
+//Default sets the value returned when the extractor does not find a value
+func (e Extractor) Default(defaultValue string) Extractor {
+	return e.With("defaultValue", defaultValue)
+}
+
+//Match sets the matcher that decides whether the template applies to the content
+func (tb *TemplateBuilder) Match(matcher Matcher) *TemplateBuilder {
+	tb.definition["matchers"] = map[string]interface{}(matcher)
+	return tb
+}
+
+//Section adds a section that runs the extractors on the content selected by the selector
+func (tb *TemplateBuilder) Section(selector Selector, extractors ...Extractor) *TemplateBuilder {
+	return tb.appendTo("sections", sectionDefinition(selector, extractors))
+}
+
+//SectionWithID adds a section with an id, so that a template extending this one can replace it
+func (tb *TemplateBuilder) SectionWithID(id string, selector Selector, extractors ...Extractor) *TemplateBuilder {
+	definition := sectionDefinition(selector, extractors)
+	definition["id"] = id
+	return tb.appendTo("sections", definition)
+}
+
+//Compute adds a computed attribute evaluated from the extracted attributes
+func (tb *TemplateBuilder) Compute(attributeName string, expression string) *TemplateBuilder {
+	return tb.appendTo("computedAttributes", map[string]interface{}{"attributeName": attributeName, "expression": expression})
+}
+
+//Assert adds an assertion that should hold for the extracted attributes
+func (tb *TemplateBuilder) Assert(name string, expression string) *TemplateBuilder {
+	return tb.appendTo("assertions", map[string]interface{}{"name": name, "expression": expression})
+}
+
+//Extends makes the template inherit from the template with the given name
+func (tb *TemplateBuilder) Extends(baseName string) *TemplateBuilder {
+	tb.definition["extends"] = baseName
+	return tb
+}
+
+//Abstract marks the template as a base for other templates, which never matches content on its own
+func (tb *TemplateBuilder) Abstract() *TemplateBuilder {
+	tb.definition["abstract"] = true
+	return tb
+}
+
+//Set sets any other template level key of the JSON DSL, for instance pageFurniture
+func (tb *TemplateBuilder) Set(key string, value interface{}) *TemplateBuilder {
+	tb.definition[key] = value
+	return tb
+}
+
+//ToJSON returns the definition of the template in the JSON DSL
+func (tb *TemplateBuilder) ToJSON() ([]byte, error) {
+	var definition bytes.Buffer
+
+	if err := writeJSON(&definition, normalizeDefinition(tb.definition), ""); err != nil {
+		return nil, err
+	}
+
+	return definition.Bytes(), nil
+}
+
+//Build builds the template the same way LoadConfig does and returns it as Templates
+func (tb *TemplateBuilder) Build() (Templates, error) {
+	return BuildTemplates(tb)
+}
+
+//BuildTemplates builds all the templates together, so that they can extend each other
+func BuildTemplates(builders ...*TemplateBuilder) (Templates, error) {
+	definitions := make([][]byte, 0, len(builders))
+
+	for _, builder := range builders {
+		definition, err := builder.ToJSON()
+
+		if err != nil {
+			return nil, err
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	return loadConfigBytes(append(append([]byte(`{"templates":[`), bytes.Join(definitions, []byte(","))...), []byte("]}")...))
+}
+
+func (tb *TemplateBuilder) appendTo(key string, definition map[string]interface{}) *TemplateBuilder {
+	existing, _ := tb.definition[key].([]interface{})
+	tb.definition[key] = append(existing, definition)
+	return tb
+}
+
+func sectionDefinition(selector Selector, extractors []Extractor) map[string]interface{} {
+	extractorDefinitions := make([]interface{}, 0, len(extractors))
+
+	for _, extractor := range extractors {
+		extractorDefinitions = append(extractorDefinitions, map[string]interface{}(extractor))
+	}
+
+	return map[string]interface{}{
+		"contentSelector":   map[string]interface{}(selector),
+		"contentExtractors": extractorDefinitions,
+	}
+}
+
+//normalizeDefinition converts the named definition types, which can be used as option values too, to plain maps for writeJSON
+func normalizeDefinition(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case Matcher:
+		return normalizeDefinition(map[string]interface{}(typedValue))
+	case Selector:
+		return normalizeDefinition(map[string]interface{}(typedValue))
+	case Extractor:
+		return normalizeDefinition(map[string]interface{}(typedValue))
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(typedValue))
+		for key, entry := range typedValue {
+			normalized[key] = normalizeDefinition(entry)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, 0, len(typedValue))
+		for _, entry := range typedValue {
+			normalized = append(normalized, normalizeDefinition(entry))
+		}
+		return normalized
+	case []string:
+		normalized := make([]interface{}, 0, len(typedValue))
+		for _, entry := range typedValue {
+			normalized = append(normalized, entry)
+		}
+		return normalized
+	}
+
+	return value
+}
@@ -0,0 +1,81 @@
+package osmosis
+
+import (
+	"strings"
+	"testing"
+)
+
+func olaTemplateBuilder() *TemplateBuilder {
+	return NewTemplate("Ola").
+		Match(Or(OneOf("ANI Technologies"), AllOf("Ola", "Invoice ID"))).
+		Section(Lines(1, 2), RegexExtract("invoiceNumber", `Invoice ID\s+([A-Z0-9]+)`, 1).Default("NA")).
+		Section(TextBlock("Customer Name", "Description").Then(RegexSelect(`Name\s+(\w+)`, 1)), NewExtractor("autoKeyValueExtractor", "").With("attributePrefix", "rider")).
+		Compute("reference", `concat("OLA-", invoiceNumber)`)
+}
+
+func TestThatBuiltTemplateExtractsLikeALoadedOne(t *testing.T) {
+	templates, err := olaTemplateBuilder().Build()
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader(contentString))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be raised but was %s", err.Error())
+	}
+
+	values := attributeValues(keyValuePairs)
+
+	if values["invoiceNumber"] != "1IE88NHTQ55547" || values["reference"] != "OLA-1IE88NHTQ55547" {
+		t.Errorf("Expected values extracted by the built template but got %v", values)
+	}
+}
+
+func TestThatBuiltTemplateCanBeWrittenToJSONAndLoadedAgain(t *testing.T) {
+	definition, err := olaTemplateBuilder().ToJSON()
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	if !strings.Contains(string(definition), `"regex":"Invoice ID\s+([A-Z0-9]+)"`) {
+		t.Errorf("Expected regex to be written the way the JSON DSL expects but got %s", string(definition))
+	}
+
+	templates, err := LoadConfig(strings.NewReader(`{"templates": [` + string(definition) + `]}`))
+
+	if err != nil {
+		t.Fatalf("Did not expect error loading the written template. But was %s", err.Error())
+	}
+
+	keyValuePairs, _ := templates.ParseText(strings.NewReader(contentString))
+
+	if attributeValues(keyValuePairs)["invoiceNumber"] != "1IE88NHTQ55547" {
+		t.Errorf("Expected the loaded template to extract the invoice number but got %v", keyValuePairs)
+	}
+}
+
+func TestThatBuiltTemplatesCanExtendEachOther(t *testing.T) {
+	templates, err := BuildTemplates(
+		NewTemplate("Base").Abstract().SectionWithID("invoice", Lines(1, 1), RegexExtract("invoiceNumber", `Invoice ID\s+(\w+)`, 1)),
+		NewTemplate("Ola").Extends("Base").Match(MatchRegex(`ANI\s+Technologies`)),
+	)
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, _ := templates.ParseText(strings.NewReader(contentString))
+
+	if len(keyValuePairs) != 1 || keyValuePairs[0].AttributeValue != "1IE88NHTQ55547" {
+		t.Errorf("Expected the inherited section to extract the invoice number but got %v", keyValuePairs)
+	}
+}
+
+func TestThatBuildingAnInvalidTemplateReturnsError(t *testing.T) {
+	if _, err := NewTemplate("No matcher").Section(Lines(1, 1)).Build(); err == nil {
+		t.Errorf("Expected an error for a template without matcher")
+	}
+}
@@ -0,0 +1,107 @@
+package osmosis
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/buger/jsonparser"
+)
+
+type computedAttribute struct {
+	AttributeName string
+	Expression    string
+	DefaultValue  string
+	Evaluate      expression
+}
+
+func buildComputedAttributes(templateDef []byte) ([]computedAttribute, error) {
+	computedAttributes := make([]computedAttribute, 0)
+	var parseError error
+
+	jsonparser.ArrayEach(templateDef, func(value []byte, dataType jsonparser.ValueType, offset int, err error) {
+		if err != nil {
+			parseError = err
+			return
+		}
+
+		attribute, err := getComputedAttribute(value)
+
+		if err != nil {
+			parseError = err
+			return
+		}
+
+		computedAttributes = append(computedAttributes, attribute)
+	}, "computedAttributes")
+
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	return computedAttributes, nil
+}
+
+func getComputedAttribute(value []byte) (computedAttribute, error) {
+	attributeName, err := jsonparser.GetString(value, "attributeName")
+
+	if err != nil {
+		return computedAttribute{}, fmt.Errorf("ERROR: Computed attribute does not specify an attributeName")
+	}
+
+	expressionText, err := jsonparser.GetString(value, "expression")
+
+	if err != nil {
+		return computedAttribute{}, fmt.Errorf("ERROR: Computed attribute %s does not specify an expression", attributeName)
+	}
+
+	defaultValue, _ := jsonparser.GetString(value, "defaultValue")
+
+	compiledExpression, err := compileExpression(expressionText)
+
+	if err != nil {
+		return computedAttribute{}, fmt.Errorf("ERROR: Could not compile expression for computed attribute %s. Error is %s", attributeName, err.Error())
+	}
+
+	return computedAttribute{
+		AttributeName: attributeName,
+		Expression:    expressionText,
+		DefaultValue:  defaultValue,
+		Evaluate:      compiledExpression,
+	}, nil
+}
+
+//computeAttributes evaluates computed attributes in their configured order. Each computed value is visible to the ones after it.
+func computeAttributes(computedAttributes []computedAttribute, extracted []ExtractedContent) []ExtractedContent {
+	scope := expressionScope{Attributes: attributeValues(extracted)}
+	computedValues := make([]ExtractedContent, 0, len(computedAttributes))
+
+	for _, attribute := range computedAttributes {
+		computedValue := ExtractedContent{
+			AttributeName:  attribute.AttributeName,
+			AttributeValue: attribute.DefaultValue,
+		}
+
+		result, err := attribute.Evaluate(scope)
+
+		if err != nil {
+			log.Printf("WARN: Could not compute attribute %s. Using default value. Error is %s", attribute.AttributeName, err.Error())
+		} else {
+			computedValue.AttributeValue = formatValue(result)
+		}
+
+		scope.Attributes[attribute.AttributeName] = computedValue.AttributeValue
+		computedValues = append(computedValues, computedValue)
+	}
+
+	return computedValues
+}
+
+func attributeValues(extracted []ExtractedContent) map[string]string {
+	values := map[string]string{}
+
+	for _, keyValue := range extracted {
+		values[keyValue.AttributeName] = keyValue.AttributeValue
+	}
+
+	return values
+}
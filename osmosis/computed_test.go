@@ -0,0 +1,101 @@
+package osmosis
+
+import (
+	"strings"
+	"testing"
+)
+
+var computedConfig = `{
+	"templates": [
+		{
+			"templateName": "Ola",
+			"matchers": {
+				"matcherType": "oneWordMatcher",
+				"words": "ANI Technologies"
+			},
+			"sections" : [
+				{
+					"contentSelector": {
+						"selectorType": "lineNumberSelector",
+						"fromLine" : 1,
+						"toLine": 2
+					},
+					"contentExtractors": [
+						{
+							"extractorType": "regexExtractor",
+							"regex": "Invoice ID\s+([A-Z0-9]+)",
+							"attributeName": "invoiceNumber",
+							"defaultValue":"NA",
+							"groupNumber":1
+						},
+						{
+							"extractorType": "regexExtractor",
+							"regex": "Karnataka\s+(\d+)",
+							"attributeName": "pinCode",
+							"defaultValue":"NA",
+							"groupNumber":1
+						}
+					]
+				}
+			],
+			"computedAttributes": [
+				{
+					"attributeName": "reference",
+					"expression": "concat(\"OLA-\", invoiceNumber)"
+				},
+				{
+					"attributeName": "nextPinCode",
+					"expression": "pinCode + 1"
+				},
+				{
+					"attributeName": "discountedFare",
+					"expression": "fare - discount",
+					"defaultValue": "NA"
+				}
+			]
+		}
+	]
+}
+`
+
+func TestThatComputedAttributesAreAppendedAfterSections(t *testing.T) {
+	templates, err := LoadConfig(strings.NewReader(computedConfig))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader(contentString))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be raised but was %s", err.Error())
+	}
+
+	expected := []ExtractedContent{
+		{AttributeName: "invoiceNumber", AttributeValue: "1IE88NHTQ55547", Block: 1, Page: 1},
+		{AttributeName: "pinCode", AttributeValue: "560000", Block: 1, Page: 1},
+		{AttributeName: "reference", AttributeValue: "OLA-1IE88NHTQ55547"},
+		{AttributeName: "nextPinCode", AttributeValue: "560001"},
+		{AttributeName: "discountedFare", AttributeValue: "NA"},
+	}
+
+	if len(keyValuePairs) != len(expected) {
+		t.Fatalf("Expected %d key value pairs but got %v", len(expected), keyValuePairs)
+	}
+
+	for index, keyValue := range expected {
+		if keyValuePairs[index] != keyValue {
+			t.Errorf("Expected %v at position %d but got %v", keyValue, index, keyValuePairs[index])
+		}
+	}
+}
+
+func TestThatComputedAttributeWithInvalidExpressionFailsConfigLoad(t *testing.T) {
+	invalidConfig := strings.Replace(computedConfig, "pinCode + 1", "pinCode +", 1)
+
+	_, err := LoadConfig(strings.NewReader(invalidConfig))
+
+	if err == nil || !strings.Contains(err.Error(), "nextPinCode") {
+		t.Errorf("Expected config load to fail for computed attribute nextPinCode")
+	}
+}
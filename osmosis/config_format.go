@@ -0,0 +1,164 @@
+package osmosis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	jsonFormat = "json"
+	yamlFormat = "yaml"
+	tomlFormat = "toml"
+)
+
+//rawStringKeys are the config keys whose values are read without unescaping, which is why a regex in a JSON config can be written
+//with a single backslash. YAML and TOML values of these keys are written to JSON the same way so that they keep working.
+var rawStringKeys = map[string]bool{
+	"regex":       true,
+	"fromRegex":   true,
+	"toRegex":     true,
+	"anchorRegex": true,
+	"headerRegex": true,
+	"endRegex":    true,
+	"separators":  true,
+	"patterns":    true,
+}
+
+var tomlLineRegex = regexp.MustCompile(`^\s*(\[\[?[\w."-]+\]\]?|[\w."-]+\s*=)`)
+
+//detectConfigFormat uses the extension of the file name when there is one and otherwise looks at the content. JSON starts with a
+//brace, TOML with a table header or a key = value line, everything else is read as YAML.
+func detectConfigFormat(name string, config []byte) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		return jsonFormat
+	case ".yaml", ".yml":
+		return yamlFormat
+	case ".toml":
+		return tomlFormat
+	}
+
+	for _, line := range strings.Split(string(config), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "{") {
+			return jsonFormat
+		}
+
+		if tomlLineRegex.MatchString(line) {
+			return tomlFormat
+		}
+
+		return yamlFormat
+	}
+
+	return jsonFormat
+}
+
+//configToJSON converts a YAML or TOML config to the JSON DSL, so that all formats share the same parsing
+func configToJSON(name string, config []byte) ([]byte, error) {
+	var decoded interface{}
+	format := detectConfigFormat(name, config)
+
+	switch format {
+	case jsonFormat:
+		return config, nil
+	case yamlFormat:
+		if err := yaml.Unmarshal(config, &decoded); err != nil {
+			return nil, fmt.Errorf("ERROR: Could not parse YAML config %s. Error is %s", name, err.Error())
+		}
+	case tomlFormat:
+		tomlConfig := map[string]interface{}{}
+		if _, err := toml.Decode(string(config), &tomlConfig); err != nil {
+			return nil, fmt.Errorf("ERROR: Could not parse TOML config %s. Error is %s", name, err.Error())
+		}
+		decoded = tomlConfig
+	}
+
+	var converted bytes.Buffer
+
+	if err := writeJSON(&converted, decoded, ""); err != nil {
+		return nil, fmt.Errorf("ERROR: Could not convert %s config %s to JSON. Error is %s", format, name, err.Error())
+	}
+
+	return converted.Bytes(), nil
+}
+
+func writeJSON(buffer *bytes.Buffer, value interface{}, key string) error {
+	switch typedValue := value.(type) {
+	case map[interface{}]interface{}:
+		stringKeyed := make(map[string]interface{}, len(typedValue))
+		for entryKey, entryValue := range typedValue {
+			stringKeyed[fmt.Sprint(entryKey)] = entryValue
+		}
+		return writeJSON(buffer, stringKeyed, key)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typedValue))
+		for entryKey := range typedValue {
+			keys = append(keys, entryKey)
+		}
+		sort.Strings(keys)
+
+		buffer.WriteString("{")
+		for index, entryKey := range keys {
+			if index > 0 {
+				buffer.WriteString(",")
+			}
+			encodedKey, _ := json.Marshal(entryKey)
+			buffer.Write(encodedKey)
+			buffer.WriteString(":")
+			if err := writeJSON(buffer, typedValue[entryKey], entryKey); err != nil {
+				return err
+			}
+		}
+		buffer.WriteString("}")
+	case []map[string]interface{}:
+		items := make([]interface{}, 0, len(typedValue))
+		for _, item := range typedValue {
+			items = append(items, item)
+		}
+		return writeJSON(buffer, items, key)
+	case []interface{}:
+		buffer.WriteString("[")
+		for index, item := range typedValue {
+			if index > 0 {
+				buffer.WriteString(",")
+			}
+			if err := writeJSON(buffer, item, key); err != nil {
+				return err
+			}
+		}
+		buffer.WriteString("]")
+	case string:
+		if rawStringKeys[key] {
+			//A YAML block scalar written with | keeps its final line break, which would become part of the regex
+			buffer.WriteString(`"` + rawStringReplacer.Replace(strings.TrimRight(typedValue, "\r\n")) + `"`)
+			return nil
+		}
+		encoded, _ := json.Marshal(typedValue)
+		buffer.Write(encoded)
+	default:
+		encoded, err := json.Marshal(typedValue)
+		if err != nil {
+			return err
+		}
+		buffer.Write(encoded)
+	}
+
+	return nil
+}
+
+//rawStringReplacer escapes quotes and line breaks but leaves backslashes alone, the way a regex is written in a JSON config
+var rawStringReplacer = strings.NewReplacer(`"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
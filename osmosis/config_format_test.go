@@ -0,0 +1,143 @@
+package osmosis
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var yamlConfig = `# Ola invoices
+templates:
+  - templateName: Ola
+    matchers:
+      matcherType: regexMatcher
+      regexExpression: 'ANI\s+Technologies'
+    sections:
+      - contentSelector:
+          selectorType: textBlockSelector
+          fromText: Customer Name
+          toRegex: '(?m)^Description'
+        contentExtractors:
+          - extractorType: regexExtractor
+            regex: |-
+              Customer Name\s+(\w+)
+            attributeName: customerName
+            defaultValue: NA
+            groupNumber: 1
+    computedAttributes:
+      - attributeName: greeting
+        expression: 'concat("Hello ", customerName)'
+`
+
+var tomlConfig = `[[templates]]
+templateName = "Ola"
+
+[templates.matchers]
+matcherType = "oneWordMatcher"
+words = "ANI Technologies"
+
+[[templates.sections]]
+[templates.sections.contentSelector]
+selectorType = "lineNumberSelector"
+fromLine = 1
+toLine = 1
+
+[[templates.sections.contentExtractors]]
+extractorType = "regexExtractor"
+regex = 'Invoice ID\s+([A-Z0-9]+)'
+attributeName = "invoiceNumber"
+defaultValue = "NA"
+groupNumber = 1
+`
+
+func TestThatYAMLConfigIsLoadedWithUnescapedRegexes(t *testing.T) {
+	templates, err := LoadConfig(strings.NewReader(yamlConfig))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader(contentString))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be raised but was %s", err.Error())
+	}
+
+	values := attributeValues(keyValuePairs)
+
+	if values["customerName"] != "Jacob" || values["greeting"] != "Hello Jacob" {
+		t.Errorf("Expected values extracted using YAML config but got %v", values)
+	}
+}
+
+func TestThatYAMLBlockScalarRegexKeepsNoTrailingLineBreak(t *testing.T) {
+	config := `templates:
+  - templateName: Ola
+    matchers:
+      matcherType: oneWordMatcher
+      words: ANI Technologies
+    sections:
+      - contentSelector:
+          selectorType: lineNumberSelector
+          fromLine: 1
+          toLine: 1
+        contentExtractors:
+          - extractorType: regexExtractor
+            regex: |
+              Invoice ID\s+(\w+)
+            attributeName: invoiceNumber
+            groupNumber: 1
+`
+	templates, err := LoadConfig(strings.NewReader(config))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, _ := templates.ParseText(strings.NewReader(contentString))
+
+	if values := attributeValues(keyValuePairs); values["invoiceNumber"] != "1IE88NHTQ55547" {
+		t.Errorf("Expected the regex of a | block scalar to match but got %v", values)
+	}
+}
+
+func TestThatTOMLConfigIsLoaded(t *testing.T) {
+	templates, err := LoadConfig(strings.NewReader(tomlConfig))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, _ := templates.ParseText(strings.NewReader(contentString))
+
+	if len(keyValuePairs) != 1 || keyValuePairs[0].AttributeValue != "1IE88NHTQ55547" {
+		t.Errorf("Expected invoice number extracted using TOML config but got %v", keyValuePairs)
+	}
+}
+
+func TestThatConfigFormatIsDetectedFromExtensionOrContent(t *testing.T) {
+	if detectConfigFormat("ola.yml", []byte("{}")) != yamlFormat || detectConfigFormat("ola.toml", nil) != tomlFormat {
+		t.Errorf("Expected format to be detected from the extension")
+	}
+
+	if detectConfigFormat("", []byte("  {\"templates\": []}")) != jsonFormat {
+		t.Errorf("Expected JSON to be detected from the content")
+	}
+
+	if detectConfigFormat("", []byte(tomlConfig)) != tomlFormat || detectConfigFormat("", []byte(yamlConfig)) != yamlFormat {
+		t.Errorf("Expected TOML and YAML to be detected from the content")
+	}
+}
+
+func TestThatConfigFilesInDifferentFormatsCanBeMixed(t *testing.T) {
+	fsys := fstest.MapFS{
+		"ola.yaml": {Data: []byte(strings.Replace(yamlConfig, "templateName: Ola", "templateName: Ola YAML", 1))},
+		"ola.toml": {Data: []byte(tomlConfig)},
+	}
+
+	templates, err := LoadConfigFS(fsys)
+
+	if err != nil || len(templates) != 2 {
+		t.Errorf("Expected templates from both YAML and TOML files but got %d and error %v", len(templates), err)
+	}
+}
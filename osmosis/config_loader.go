@@ -0,0 +1,321 @@
+package osmosis
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/buger/jsonparser"
+)
+
+//configLoader merges the templates and definitions of many config files into a single config. Each file can include other files
+//using a list of paths or glob patterns in its include block. Paths are relative to the file that includes them.
+//Variables are resolved once every file is loaded. A file uses the supplied variables, the environment, its own variables block and
+//then the variables blocks of the other files, so a variables file included next to the templates applies to all of them.
+type configLoader struct {
+	ReadFile          func(name string) ([]byte, error)
+	Glob              func(pattern string) ([]string, error)
+	IncludePath       func(from string, include string) string
+	Variables         map[string]string
+	Loaded            map[string]bool
+	ConfigNames       []string
+	Configs           map[string][]byte
+	FileVariables     map[string]map[string]string
+	Templates         [][]byte
+	TemplateSources   map[string]string
+	Definitions       map[string][]byte
+	DefinitionSources map[string]string
+	DefinitionNames   []string
+}
+
+//LoadConfigFiles loads templates from each of the config files and the files they include.
+//A template name or definition name can only be used once across all the files.
+func LoadConfigFiles(paths ...string) (Templates, error) {
+	return LoadConfigFilesWithVariables(nil, paths...)
+}
+
+//LoadConfigFilesWithVariables loads the config files like LoadConfigFiles. Values in the variables map take precedence over
+//environment variables and the variables blocks of the files when ${NAME} is replaced.
+func LoadConfigFilesWithVariables(variables map[string]string, paths ...string) (Templates, error) {
+	loader := newFileConfigLoader()
+	loader.Variables = variables
+	return loader.loadAll(paths)
+}
+
+//LoadConfigDir loads templates from every JSON, YAML and TOML config file in the directory and its subdirectories
+func LoadConfigDir(dir string) (Templates, error) {
+	return LoadConfigFS(os.DirFS(dir))
+}
+
+//LoadConfigDirWithVariables loads the config files in the directory like LoadConfigDir, using the variables like
+//LoadConfigFilesWithVariables
+func LoadConfigDirWithVariables(dir string, variables map[string]string) (Templates, error) {
+	return LoadConfigFSWithVariables(os.DirFS(dir), variables)
+}
+
+//LoadConfigFS loads templates from the config files in fsys that match any of the glob patterns, for instance configs embedded using
+//embed.FS. When no pattern is provided, every .json, .yaml, .yml and .toml file in fsys is loaded.
+func LoadConfigFS(fsys fs.FS, patterns ...string) (Templates, error) {
+	return LoadConfigFSWithVariables(fsys, nil, patterns...)
+}
+
+//LoadConfigFSWithVariables loads the config files in fsys like LoadConfigFS, using the variables like LoadConfigFilesWithVariables
+func LoadConfigFSWithVariables(fsys fs.FS, variables map[string]string, patterns ...string) (Templates, error) {
+	loader := newConfigLoader(func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	}, func(pattern string) ([]string, error) {
+		return fs.Glob(fsys, pattern)
+	}, func(from string, include string) string {
+		return path.Join(path.Dir(from), include)
+	})
+	loader.Variables = variables
+
+	names, err := configFileNames(fsys, patterns)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.loadAll(names)
+}
+
+func newFileConfigLoader() *configLoader {
+	return newConfigLoader(os.ReadFile, filepath.Glob, func(from string, include string) string {
+		if filepath.IsAbs(include) {
+			return include
+		}
+		return filepath.Join(filepath.Dir(from), include)
+	})
+}
+
+func newConfigLoader(readFile func(name string) ([]byte, error), glob func(pattern string) ([]string, error), includePath func(from string, include string) string) *configLoader {
+	return &configLoader{
+		ReadFile:          readFile,
+		Glob:              glob,
+		IncludePath:       includePath,
+		Loaded:            map[string]bool{},
+		ConfigNames:       make([]string, 0),
+		Configs:           map[string][]byte{},
+		FileVariables:     map[string]map[string]string{},
+		Templates:         make([][]byte, 0),
+		TemplateSources:   map[string]string{},
+		Definitions:       map[string][]byte{},
+		DefinitionSources: map[string]string{},
+		DefinitionNames:   make([]string, 0),
+	}
+}
+
+func configFileNames(fsys fs.FS, patterns []string) ([]string, error) {
+	names := make([]string, 0)
+
+	if len(patterns) == 0 {
+		err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && isConfigFile(name) {
+				names = append(names, name)
+			}
+			return nil
+		})
+
+		return names, err
+	}
+
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+
+		if err != nil {
+			return nil, fmt.Errorf("ERROR: Config file pattern %s is invalid. Error is %s", pattern, err.Error())
+		}
+
+		names = append(names, matches...)
+	}
+
+	return names, nil
+}
+
+func isConfigFile(name string) bool {
+	extension := strings.ToLower(path.Ext(name))
+	return extension == ".json" || extension == ".yaml" || extension == ".yml" || extension == ".toml"
+}
+
+func (cl *configLoader) loadAll(names []string) (Templates, error) {
+	for _, name := range names {
+		if err := cl.load(name); err != nil {
+			return nil, err
+		}
+	}
+
+	sharedVariables, ambiguousVariables := cl.sharedVariables()
+
+	for _, name := range cl.ConfigNames {
+		definedVariables := map[string]string{}
+
+		for variable, value := range sharedVariables {
+			definedVariables[variable] = value
+		}
+
+		for variable, value := range cl.FileVariables[name] {
+			definedVariables[variable] = value
+		}
+
+		resolver := newVariableResolver(cl.Variables, definedVariables)
+		resolver.Ambiguous = ambiguousVariables
+		config, err := resolver.resolve(cl.Configs[name])
+
+		if err != nil {
+			return nil, fmt.Errorf("ERROR: Could not resolve variables in config file %s. Error is %s", name, err.Error())
+		}
+
+		if err := cl.addConfig(name, config); err != nil {
+			return nil, err
+		}
+	}
+
+	return loadConfigBytes(cl.mergedConfig())
+}
+
+//sharedVariables merges the variables blocks of all the files. A variable that two files define differently can only be used by
+//files that define it themselves, so it is returned with the error to report for every other file.
+func (cl *configLoader) sharedVariables() (map[string]string, map[string]error) {
+	sharedVariables := map[string]string{}
+	variableSources := map[string]string{}
+	ambiguousVariables := map[string]error{}
+
+	for _, name := range cl.ConfigNames {
+		for variable, value := range cl.FileVariables[name] {
+			if source, found := variableSources[variable]; found && sharedVariables[variable] != value {
+				ambiguousVariables[variable] = fmt.Errorf("ERROR: Variable %s is defined differently in %s and %s", variable, source, name)
+				continue
+			}
+			sharedVariables[variable] = value
+			variableSources[variable] = name
+		}
+	}
+
+	for variable := range ambiguousVariables {
+		delete(sharedVariables, variable)
+	}
+
+	return sharedVariables, ambiguousVariables
+}
+
+func (cl *configLoader) load(name string) error {
+	if cl.Loaded[name] {
+		return nil
+	}
+
+	cl.Loaded[name] = true
+	config, err := cl.ReadFile(name)
+
+	if err != nil {
+		return fmt.Errorf("ERROR: Could not read config file %s. Error is %s", name, err.Error())
+	}
+
+	if config, err = configToJSON(name, config); err != nil {
+		return err
+	}
+
+	if config, _, err = migrateConfig(config); err != nil {
+		return fmt.Errorf("ERROR: Could not load config file %s. Error is %s", name, err.Error())
+	}
+
+	cl.ConfigNames = append(cl.ConfigNames, name)
+	cl.Configs[name] = config
+	cl.FileVariables[name] = configVariables(config)
+	resolver := newVariableResolver(cl.Variables, cl.FileVariables[name])
+
+	for _, include := range getStringList(config, "include") {
+		includePath, err := resolver.substitute([]byte(include), "the includes of config file "+name, nil)
+
+		if err != nil {
+			return err
+		}
+
+		includedNames, err := cl.Glob(cl.IncludePath(name, string(includePath)))
+
+		if err != nil || len(includedNames) == 0 {
+			return fmt.Errorf("ERROR: Could not find %s included from config file %s", include, name)
+		}
+
+		for _, includedName := range includedNames {
+			if err := cl.load(includedName); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+//addConfig adds the templates and definitions of a config file, once its variables are resolved
+func (cl *configLoader) addConfig(name string, config []byte) error {
+	var loadError error
+
+	jsonparser.ArrayEach(config, func(value []byte, dataType jsonparser.ValueType, offset int, err error) {
+		if loadError == nil {
+			loadError = cl.addTemplate(name, value)
+		}
+	}, "templates")
+
+	jsonparser.ObjectEach(config, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+		if dataType == jsonparser.String {
+			value = []byte(`"` + string(value) + `"`)
+		}
+		if loadError == nil {
+			loadError = cl.addDefinition(name, string(key), value)
+		}
+		return nil
+	}, "definitions")
+
+	return loadError
+}
+
+func (cl *configLoader) addTemplate(source string, templateDef []byte) error {
+	templateName, err := jsonparser.GetString(templateDef, "templateName")
+
+	if err != nil {
+		return fmt.Errorf("ERROR: Template name not specified in configuration file %s", source)
+	}
+
+	if existingSource, found := cl.TemplateSources[templateName]; found {
+		return fmt.Errorf("ERROR: Template %s is defined in both %s and %s", templateName, existingSource, source)
+	}
+
+	cl.TemplateSources[templateName] = source
+	cl.Templates = append(cl.Templates, templateDef)
+	return nil
+}
+
+func (cl *configLoader) addDefinition(source string, name string, definition []byte) error {
+	if existingSource, found := cl.DefinitionSources[name]; found {
+		return fmt.Errorf("ERROR: Definition %s is defined in both %s and %s", name, existingSource, source)
+	}
+
+	cl.DefinitionSources[name] = source
+	cl.DefinitionNames = append(cl.DefinitionNames, name)
+	cl.Definitions[name] = definition
+	return nil
+}
+
+func (cl *configLoader) mergedConfig() []byte {
+	definitions := make([][]byte, 0, len(cl.DefinitionNames))
+
+	for _, name := range cl.DefinitionNames {
+		definitions = append(definitions, append([]byte(`"`+name+`":`), cl.Definitions[name]...))
+	}
+
+	var config bytes.Buffer
+	config.WriteString(`{"definitions":{`)
+	config.Write(bytes.Join(definitions, []byte(",")))
+	config.WriteString(`},"templates":[`)
+	config.Write(bytes.Join(cl.Templates, []byte(",")))
+	config.WriteString(`]}`)
+
+	return config.Bytes()
+}
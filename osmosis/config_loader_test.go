@@ -0,0 +1,199 @@
+package osmosis
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var olaTemplateFile = `{
+	"include": ["../common/*.json"],
+	"templates": [
+		{
+			"templateName": "Ola",
+			"matchers": {"$ref": "olaMatcher"},
+			"sections" : [
+				{
+					"contentSelector": {"selectorType": "textBlockSelector"},
+					"contentExtractors": [{"$ref": "invoiceNumber"}]
+				}
+			]
+		}
+	]
+}`
+
+var commonDefinitionsFile = `{
+	"definitions": {
+		"olaMatcher": {"matcherType": "oneWordMatcher", "words": "ANI Technologies"},
+		"invoiceNumber": {
+			"extractorType": "regexExtractor",
+			"regex": "Invoice ID\s+([A-Z0-9]+)",
+			"attributeName": "invoiceNumber",
+			"defaultValue": "NA",
+			"groupNumber": 1
+		}
+	}
+}`
+
+var uberTemplateFile = `{
+	"templates": [
+		{
+			"templateName": "Uber",
+			"extends": "Ola",
+			"matchers": {"matcherType": "oneWordMatcher", "words": "Uber"}
+		}
+	]
+}`
+
+func configFS() fstest.MapFS {
+	return fstest.MapFS{
+		"vendors/ola.json":   {Data: []byte(olaTemplateFile)},
+		"vendors/uber.json":  {Data: []byte(uberTemplateFile)},
+		"common/shared.json": {Data: []byte(commonDefinitionsFile)},
+		"README.md":          {Data: []byte("Not a config")},
+	}
+}
+
+func TestThatConfigIsMergedFromAllFilesInFS(t *testing.T) {
+	templates, err := LoadConfigFS(configFS())
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	if len(templates) != 2 {
+		t.Fatalf("Expected templates from both vendor files but got %d", len(templates))
+	}
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader(contentString))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be raised but was %s", err.Error())
+	}
+
+	if len(keyValuePairs) != 1 || keyValuePairs[0].AttributeValue != "1IE88NHTQ55547" {
+		t.Errorf("Expected invoice number using the included definitions but got %v", keyValuePairs)
+	}
+}
+
+func TestThatOnlyFilesMatchingPatternsAndTheirIncludesAreLoaded(t *testing.T) {
+	templates, err := LoadConfigFS(configFS(), "vendors/ola.json")
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	if _, found := templates["Uber"]; found || len(templates) != 1 {
+		t.Errorf("Expected only the Ola template to be loaded but got %d templates", len(templates))
+	}
+}
+
+func TestThatDuplicateTemplateNamesAcrossFilesReturnError(t *testing.T) {
+	fsys := configFS()
+	fsys["vendors/ola_copy.json"] = &fstest.MapFile{Data: []byte(olaTemplateFile)}
+
+	_, err := LoadConfigFS(fsys)
+
+	if err == nil || !strings.Contains(err.Error(), "vendors/ola.json") || !strings.Contains(err.Error(), "vendors/ola_copy.json") {
+		t.Errorf("Expected duplicate template error naming both files but got %v", err)
+	}
+}
+
+func TestThatDuplicateTemplateNamesInASingleConfigReturnError(t *testing.T) {
+	config := `{"templates": [
+		{"templateName": "Ola", "matchers": {"matcherType": "oneWordMatcher", "words": "Ola"}},
+		{"templateName": "Ola", "matchers": {"matcherType": "oneWordMatcher", "words": "ANI"}}
+	]}`
+
+	if _, err := LoadConfig(strings.NewReader(config)); err == nil {
+		t.Errorf("Expected duplicate template name to return an error")
+	}
+}
+
+func TestThatMissingIncludeReturnsError(t *testing.T) {
+	fsys := fstest.MapFS{"ola.json": {Data: []byte(`{"include": ["missing.json"], "templates": []}`)}}
+
+	if _, err := LoadConfigFS(fsys); err == nil || !strings.Contains(err.Error(), "missing.json") {
+		t.Errorf("Expected missing include to return an error but got %v", err)
+	}
+}
+
+func TestThatConfigFilesAndDirectoriesCanBeLoadedFromDisk(t *testing.T) {
+	dir := t.TempDir()
+
+	for name, file := range configFS() {
+		os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755)
+		os.WriteFile(filepath.Join(dir, name), file.Data, 0644)
+	}
+
+	fromFiles, err := LoadConfigFiles(filepath.Join(dir, "vendors", "ola.json"), filepath.Join(dir, "vendors", "uber.json"))
+
+	if err != nil || len(fromFiles) != 2 {
+		t.Errorf("Expected both templates to be loaded from files but got %d and error %v", len(fromFiles), err)
+	}
+
+	fromDir, err := LoadConfigDir(dir)
+
+	if err != nil || len(fromDir) != 2 {
+		t.Errorf("Expected both templates to be loaded from directory but got %d and error %v", len(fromDir), err)
+	}
+}
+
+func regionalConfigFS() fstest.MapFS {
+	return fstest.MapFS{
+		"main.json":       {Data: []byte(`{"include": ["regions/${REGION}.json", "vendors/*.json"]}`)},
+		"regions/in.json": {Data: []byte(`{"variables": {"COMPANY": "ANI Technologies", "CURRENCY": "₹"}}`)},
+		"regions/eu.json": {Data: []byte(`{"variables": {"COMPANY": "Ola Europe", "CURRENCY": "€"}}`)},
+		"vendors/ola.json": {Data: []byte(`{"templates": [{
+			"templateName": "Ola",
+			"matchers": {"matcherType": "oneWordMatcher", "words": "${COMPANY}"},
+			"sections": [{
+				"contentSelector": {"selectorType": "lineNumberSelector", "fromLine": 1, "toLine": 1},
+				"contentExtractors": [{"extractorType": "regexExtractor", "regex": "(Domlur)", "attributeName": "currency", "defaultValue": "${CURRENCY}", "groupNumber": 2}]
+			}]
+		}]}`)},
+	}
+}
+
+func TestThatVariablesOfAnIncludedFileApplyToTheOtherConfigFiles(t *testing.T) {
+	templates, err := LoadConfigFSWithVariables(regionalConfigFS(), map[string]string{"REGION": "in"}, "main.json")
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, _ := templates.ParseText(strings.NewReader(contentString))
+
+	if values := attributeValues(keyValuePairs); values["currency"] != "₹" {
+		t.Errorf("Expected the variables of the included region file to be used but got %v", values)
+	}
+
+	dir := t.TempDir()
+
+	for name, file := range regionalConfigFS() {
+		os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755)
+		os.WriteFile(filepath.Join(dir, name), file.Data, 0644)
+	}
+
+	store, err := NewTemplateStoreWithVariables(map[string]string{"REGION": "in", "CURRENCY": "INR"}, filepath.Join(dir, "main.json"))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, _ = store.ParseText(strings.NewReader(contentString))
+
+	if values := attributeValues(keyValuePairs); values["currency"] != "INR" {
+		t.Errorf("Expected the supplied variables to take precedence in the template store but got %v", values)
+	}
+}
+
+func TestThatVariablesDefinedDifferentlyInOtherFilesReturnError(t *testing.T) {
+	_, err := LoadConfigFS(regionalConfigFS(), "regions/*.json", "vendors/*.json")
+
+	if err == nil || !strings.Contains(err.Error(), "defined differently") {
+		t.Errorf("Expected a variable defined differently in two region files to return an error but got %v", err)
+	}
+}
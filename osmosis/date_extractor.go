@@ -0,0 +1,439 @@
+package osmosis
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/buger/jsonparser"
+)
+
+const (
+	dayFirstOrder   = "dayFirst"
+	monthFirstOrder = "monthFirst"
+)
+
+type dateExtractor struct {
+	AttributeName string
+	DefaultValue  string
+	Layouts       []string
+	Locale        string
+	Timezone      string
+	DateOrder     string
+}
+
+type dateLayout struct {
+	Layout string
+	Order  string
+}
+
+type parsedDate struct {
+	Value time.Time
+	Order string
+}
+
+type dateCandidate struct {
+	Text  string
+	Dates []parsedDate
+}
+
+type layoutDirective struct {
+	Directive string
+	Pattern   string
+}
+
+//monthNames maps the month names of a locale to the english abbreviations understood by the time package
+var monthNames = map[string]map[string]string{
+	"fr": {"janvier": "Jan", "février": "Feb", "fevrier": "Feb", "mars": "Mar", "avril": "Apr", "mai": "May", "juin": "Jun", "juillet": "Jul", "août": "Aug", "aout": "Aug", "septembre": "Sep", "octobre": "Oct", "novembre": "Nov", "décembre": "Dec", "decembre": "Dec"},
+	"de": {"januar": "Jan", "februar": "Feb", "märz": "Mar", "maerz": "Mar", "april": "Apr", "mai": "May", "juni": "Jun", "juli": "Jul", "august": "Aug", "september": "Sep", "oktober": "Oct", "november": "Nov", "dezember": "Dec"},
+	"es": {"enero": "Jan", "febrero": "Feb", "marzo": "Mar", "abril": "Apr", "mayo": "May", "junio": "Jun", "julio": "Jul", "agosto": "Aug", "septiembre": "Sep", "setiembre": "Sep", "octubre": "Oct", "noviembre": "Nov", "diciembre": "Dec"},
+}
+
+//monthTranslator replaces the month names of a locale by the english abbreviations understood by the time package.
+//Names are only replaced as whole words, so that e.g. mai is not found inside email.
+type monthTranslator struct {
+	Names map[string]string
+	Regex *regexp.Regexp
+}
+
+//monthTranslators are compiled once for every locale in monthNames
+var monthTranslators = compileMonthTranslators()
+
+var englishMonths = `jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sept?(?:ember)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?`
+
+var defaultDateLayouts = []dateLayout{
+	{Layout: "2006/1/2"},
+	{Layout: "2/1/2006", Order: dayFirstOrder},
+	{Layout: "2/1/06", Order: dayFirstOrder},
+	{Layout: "1/2/2006", Order: monthFirstOrder},
+	{Layout: "1/2/06", Order: monthFirstOrder},
+	{Layout: "2 Jan 2006"},
+	{Layout: "2 January 2006"},
+	{Layout: "2 Jan 06"},
+	{Layout: "Jan 2 2006"},
+	{Layout: "January 2 2006"},
+	{Layout: "Jan 2 06"},
+}
+
+var defaultTimeLayouts = []string{"15:04:05", "15:04", "3:04:05PM", "3:04PM"}
+
+//layoutDirectives are the elements of a go time layout, longest first so that e.g. January is not read as Jan
+var layoutDirectives = []layoutDirective{
+	{Directive: "January", Pattern: `(?:` + englishMonths + `)`},
+	{Directive: "Monday", Pattern: `[a-z]+`},
+	{Directive: "Z07:00", Pattern: `(?:Z|[+-]\d{2}:\d{2})`},
+	{Directive: "-07:00", Pattern: `[+-]\d{2}:\d{2}`},
+	{Directive: "-0700", Pattern: `[+-]\d{4}`},
+	{Directive: "2006", Pattern: `\d{4}`},
+	{Directive: "Jan", Pattern: `(?:` + englishMonths + `)`},
+	{Directive: "Mon", Pattern: `[a-z]{3}`},
+	{Directive: "MST", Pattern: `[a-z]{3,4}`},
+	{Directive: "_2", Pattern: `\s?\d{1,2}`},
+	{Directive: "01", Pattern: `\d{2}`},
+	{Directive: "02", Pattern: `\d{2}`},
+	{Directive: "03", Pattern: `\d{2}`},
+	{Directive: "04", Pattern: `\d{2}`},
+	{Directive: "05", Pattern: `\d{2}`},
+	{Directive: "06", Pattern: `\d{2}`},
+	{Directive: "15", Pattern: `\d{1,2}`},
+	{Directive: "PM", Pattern: `[ap]m`},
+	{Directive: "pm", Pattern: `[ap]m`},
+	{Directive: "1", Pattern: `\d{1,2}`},
+	{Directive: "2", Pattern: `\d{1,2}`},
+	{Directive: "3", Pattern: `\d{1,2}`},
+	{Directive: "4", Pattern: `\d{1,2}`},
+	{Directive: "5", Pattern: `\d{1,2}`},
+}
+
+var ordinalSuffixRegex = regexp.MustCompile(`(?i)(\d)(st|nd|rd|th)\b`)
+var dayDirectiveRegex = regexp.MustCompile(`_2|\b0?2\b`)
+var monthDirectiveRegex = regexp.MustCompile(`Jan|\b0?1\b`)
+var septemberRegex = regexp.MustCompile(`(?i)\bsept\b`)
+var monthNameRegex = regexp.MustCompile(`(?i)\b(?:` + englishMonths + `)\b`)
+
+func getDateExtractor(value []byte) dateExtractor {
+	attributeName, _ := jsonparser.GetString(value, "attributeName")
+	defaultValue, _ := jsonparser.GetString(value, "defaultValue")
+	locale, _ := jsonparser.GetString(value, "locale")
+	timezone, _ := jsonparser.GetString(value, "timezone")
+	dateOrder, _ := jsonparser.GetString(value, "dateOrder")
+	layouts := make([]string, 0)
+
+	jsonparser.ArrayEach(value, func(layout []byte, dataType jsonparser.ValueType, offset int, err error) {
+		layouts = append(layouts, string(layout))
+	}, "layouts")
+
+	return dateExtractor{
+		AttributeName: attributeName,
+		DefaultValue:  defaultValue,
+		Layouts:       layouts,
+		Locale:        locale,
+		Timezone:      timezone,
+		DateOrder:     dateOrder,
+	}
+}
+
+func (de dateExtractor) asContentExtractor() (contentExtractor, error) {
+	location, err := time.LoadLocation(de.Timezone)
+
+	if err != nil {
+		return nil, fmt.Errorf("ERROR: Unknown timezone %s for date extractor. Error is %s", de.Timezone, err.Error())
+	}
+
+	if de.DateOrder != "" && de.DateOrder != dayFirstOrder && de.DateOrder != monthFirstOrder {
+		return nil, fmt.Errorf("ERROR: Date order should be either %s or %s but was %s", dayFirstOrder, monthFirstOrder, de.DateOrder)
+	}
+
+	localeMonths, found := monthTranslators[strings.ToLower(de.Locale)]
+
+	if !found && de.Locale != "" && !strings.EqualFold(de.Locale, "en") {
+		return nil, fmt.Errorf("ERROR: Unsupported locale %s for date extractor", de.Locale)
+	}
+
+	findCandidates, err := de.candidateFinder(localeMonths, location)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c content) []ExtractedContent {
+		extractedKeyVal := ExtractedContent{
+			AttributeName:  de.AttributeName,
+			AttributeValue: de.DefaultValue,
+		}
+
+		for _, candidate := range findCandidates(c.OriginalText) {
+			dates := resolveDateOrder(candidate.Dates, de.DateOrder)
+
+			if len(dates) == 1 {
+				extractedKeyVal.AttributeValue = dates[0].Value.Format(time.RFC3339)
+				return []ExtractedContent{extractedKeyVal}
+			}
+
+			if len(dates) > 1 {
+				interpretations := make([]string, 0, len(dates))
+				for _, date := range dates {
+					interpretations = append(interpretations, date.Value.Format(time.RFC3339))
+				}
+				extractedKeyVal.Warning = fmt.Sprintf("Date %q is ambiguous. It could be any of %s. Configure dateOrder to resolve it", candidate.Text, strings.Join(interpretations, ", "))
+				return []ExtractedContent{extractedKeyVal}
+			}
+		}
+
+		return []ExtractedContent{extractedKeyVal}
+	}, nil
+}
+
+//candidateFinder returns a function that locates date expressions in text, in the order they appear, along with every date
+//each of them can be parsed to. Configured layouts are turned into patterns of their own, otherwise a set of common formats is used.
+func (de dateExtractor) candidateFinder(localeMonths monthTranslator, location *time.Location) (func(text string) []dateCandidate, error) {
+	if len(de.Layouts) > 0 {
+		return layoutCandidateFinder(de.Layouts, localeMonths, location)
+	}
+
+	months := englishMonths
+	for name := range localeMonths.Names {
+		months = regexp.QuoteMeta(name) + "|" + months
+	}
+
+	candidateRegex, err := regexp.Compile(`(?i)\b(\d{4}[-/.]\d{1,2}[-/.]\d{1,2}|\d{1,2}[-/.]\d{1,2}[-/.]\d{2,4}|` +
+		`\d{1,2}(?:st|nd|rd|th)?[\s-]+(?:` + months + `)\.?,?[\s-]+\d{2,4}|` +
+		`(?:` + months + `)\.?\s+\d{1,2}(?:st|nd|rd|th)?,?\s+\d{2,4})\b` +
+		`(?:\s*,?\s*(?:at\s+)?(\d{1,2}:\d{2}(?::\d{2})?(?:\s*[AaPp]\.?[Mm]\.?)?))?`)
+
+	if err != nil {
+		return nil, fmt.Errorf("ERROR: Could not build the date candidate regex. Error is %s", err.Error())
+	}
+
+	return func(text string) []dateCandidate {
+		candidates := make([]dateCandidate, 0)
+
+		for _, match := range candidateRegex.FindAllStringSubmatch(text, -1) {
+			candidates = append(candidates, dateCandidate{
+				Text:  strings.TrimSpace(match[0]),
+				Dates: parseDateAndTime(normalizeDateText(match[1], localeMonths), match[2], location),
+			})
+		}
+
+		return candidates
+	}, nil
+}
+
+func layoutCandidateFinder(layouts []string, localeMonths monthTranslator, location *time.Location) (func(text string) []dateCandidate, error) {
+	layoutRegexes := make([]*regexp.Regexp, 0, len(layouts))
+
+	for _, layout := range layouts {
+		compiledRegex, err := regexp.Compile(`(?i)` + layoutPattern(layout))
+
+		if err != nil {
+			return nil, fmt.Errorf("ERROR: Could not build a pattern for date layout %s. Error is %s", layout, err.Error())
+		}
+
+		layoutRegexes = append(layoutRegexes, compiledRegex)
+	}
+
+	return func(text string) []dateCandidate {
+		translated := localeMonths.translate(ordinalSuffixRegex.ReplaceAllString(text, "$1"))
+		candidatesAt := map[int]*dateCandidate{}
+		positions := make([]int, 0)
+
+		for index, layoutRegex := range layoutRegexes {
+			layout := dateLayout{Layout: strings.Replace(layouts[index], "January", "Jan", -1), Order: layoutOrder(layouts[index])}
+
+			for _, bounds := range layoutRegex.FindAllStringIndex(translated, -1) {
+				matchedText := monthNameRegex.ReplaceAllStringFunc(translated[bounds[0]:bounds[1]], func(month string) string {
+					return month[:3]
+				})
+				candidate, found := candidatesAt[bounds[0]]
+
+				if !found {
+					candidate = &dateCandidate{Text: matchedText}
+					candidatesAt[bounds[0]] = candidate
+					positions = append(positions, bounds[0])
+				}
+
+				candidate.Dates = appendDistinctDates(candidate.Dates, parseWithLayouts(matchedText, []dateLayout{layout}, location))
+			}
+		}
+
+		sort.Ints(positions)
+		candidates := make([]dateCandidate, 0, len(positions))
+
+		for _, position := range positions {
+			candidates = append(candidates, *candidatesAt[position])
+		}
+
+		return candidates
+	}, nil
+}
+
+//layoutPattern converts a go time layout into a regex matching text written in that layout
+func layoutPattern(layout string) string {
+	var pattern strings.Builder
+
+	for remaining := layout; remaining != ""; {
+		matched := false
+
+		for _, directive := range layoutDirectives {
+			if strings.HasPrefix(remaining, directive.Directive) {
+				pattern.WriteString(directive.Pattern)
+				remaining = remaining[len(directive.Directive):]
+				matched = true
+				break
+			}
+		}
+
+		if matched {
+			continue
+		}
+
+		current, size := utf8.DecodeRuneInString(remaining)
+		if unicode.IsSpace(current) {
+			pattern.WriteString(`\s+`)
+		} else {
+			pattern.WriteString(regexp.QuoteMeta(string(current)))
+		}
+		remaining = remaining[size:]
+	}
+
+	return pattern.String()
+}
+
+//normalizeDateText removes ordinal suffixes, translates month names and reduces separators to those used by the default layouts
+func normalizeDateText(text string, localeMonths monthTranslator) string {
+	text = localeMonths.translate(ordinalSuffixRegex.ReplaceAllString(text, "$1"))
+
+	if strings.IndexFunc(text, func(r rune) bool { return r > '9' }) >= 0 {
+		text = strings.NewReplacer("-", " ", ",", " ", ".", " ").Replace(text)
+	} else {
+		text = strings.NewReplacer("-", "/", ".", "/").Replace(text)
+	}
+
+	text = strings.Join(strings.Fields(text), " ")
+	return septemberRegex.ReplaceAllString(text, "Sep")
+}
+
+func (mt monthTranslator) translate(text string) string {
+	if mt.Regex == nil {
+		return text
+	}
+
+	return mt.Regex.ReplaceAllStringFunc(text, func(name string) string {
+		return mt.Names[strings.ToLower(name)]
+	})
+}
+
+func compileMonthTranslators() map[string]monthTranslator {
+	translators := map[string]monthTranslator{}
+
+	for locale, names := range monthNames {
+		quotedNames := make([]string, 0, len(names))
+		for name := range names {
+			quotedNames = append(quotedNames, regexp.QuoteMeta(name))
+		}
+		sort.Strings(quotedNames)
+		translators[locale] = monthTranslator{Names: names, Regex: regexp.MustCompile(`(?i)\b(?:` + strings.Join(quotedNames, "|") + `)\b`)}
+	}
+
+	return translators
+}
+
+func parseDateAndTime(dateText string, timeText string, location *time.Location) []parsedDate {
+	dates := parseWithLayouts(dateText, defaultDateLayouts, location)
+
+	if timeText == "" {
+		return dates
+	}
+
+	timeText = strings.ToUpper(strings.NewReplacer(" ", "", ".", "").Replace(timeText))
+
+	for _, layout := range defaultTimeLayouts {
+		timeOfDay, err := time.Parse(layout, timeText)
+
+		if err != nil {
+			continue
+		}
+
+		for index, date := range dates {
+			dates[index].Value = time.Date(date.Value.Year(), date.Value.Month(), date.Value.Day(), timeOfDay.Hour(), timeOfDay.Minute(), timeOfDay.Second(), 0, location)
+		}
+		break
+	}
+
+	return dates
+}
+
+//parseWithLayouts returns every distinct date the text can be parsed to using the provided layouts
+func parseWithLayouts(text string, layouts []dateLayout, location *time.Location) []parsedDate {
+	dates := make([]parsedDate, 0)
+
+	for _, layout := range layouts {
+		value, err := time.ParseInLocation(layout.Layout, text, location)
+
+		if err != nil {
+			continue
+		}
+
+		dates = appendDistinctDates(dates, []parsedDate{{Value: value, Order: layout.Order}})
+	}
+
+	return dates
+}
+
+func appendDistinctDates(dates []parsedDate, newDates []parsedDate) []parsedDate {
+	for _, newDate := range newDates {
+		isDuplicate := false
+		for _, date := range dates {
+			if date.Value.Equal(newDate.Value) {
+				isDuplicate = true
+			}
+		}
+
+		if !isDuplicate {
+			dates = append(dates, newDate)
+		}
+	}
+
+	return dates
+}
+
+func resolveDateOrder(dates []parsedDate, dateOrder string) []parsedDate {
+	if len(dates) < 2 || dateOrder == "" {
+		return dates
+	}
+
+	preferred := make([]parsedDate, 0)
+
+	for _, date := range dates {
+		if date.Order == dateOrder {
+			preferred = append(preferred, date)
+		}
+	}
+
+	if len(preferred) == 0 {
+		return dates
+	}
+
+	return preferred
+}
+
+//layoutOrder works out whether a configured layout places the day before the month
+func layoutOrder(layout string) string {
+	dateOnly := strings.NewReplacer("2006", "", "15", "", "03", "", "04", "", "05", "").Replace(layout)
+	dayIndex := dayDirectiveRegex.FindStringIndex(dateOnly)
+	monthIndex := monthDirectiveRegex.FindStringIndex(dateOnly)
+
+	if dayIndex == nil || monthIndex == nil {
+		return ""
+	}
+
+	if dayIndex[0] < monthIndex[0] {
+		return dayFirstOrder
+	}
+
+	return monthFirstOrder
+}
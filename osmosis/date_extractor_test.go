@@ -0,0 +1,103 @@
+package osmosis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestThatDateExtractorParsesCommonReceiptFormats(t *testing.T) {
+	config := `{"extractorType": "dateExtractor", "attributeName": "orderDate", "defaultValue": "NA", "timezone": "Asia/Kolkata"}`
+	expectations := map[string]string{
+		"Trip on Jun 12, 2018 from Domlur":               "2018-06-12T00:00:00+05:30",
+		"Ordered: 12th June 2018 09:45 PM":               "2018-06-12T21:45:00+05:30",
+		"Order Time: Wed, 16 May 2018, 10:17 AM":         "2018-05-16T10:17:00+05:30",
+		"Invoice date 2018-06-12 14:05:09":               "2018-06-12T14:05:09+05:30",
+		"Date 25/06/18 Amount 140.0":                     "2018-06-25T00:00:00+05:30",
+		"Order 140.0 with no date but 12/12/2018 repeat": "2018-12-12T00:00:00+05:30",
+	}
+
+	extractor, _ := classifyAndBuildExtractor([]byte(config))
+
+	for text, expected := range expectations {
+		c := content{OriginalText: text}
+		c.prepare()
+
+		extracted := extractor(c)[0]
+
+		if extracted.AttributeValue != expected || extracted.Warning != "" {
+			t.Errorf("Expected %s for [%s] but got %s with warning %s", expected, text, extracted.AttributeValue, extracted.Warning)
+		}
+	}
+}
+
+func TestThatAmbiguousDateIsReportedInsteadOfGuessed(t *testing.T) {
+	c := content{OriginalText: "Date: 12/06/18"}
+	c.prepare()
+
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "dateExtractor", "attributeName": "orderDate", "defaultValue": "NA"}`))
+	extracted := extractor(c)[0]
+
+	if extracted.AttributeValue != "NA" {
+		t.Errorf("Expected default value for an ambiguous date but got %s", extracted.AttributeValue)
+	}
+
+	if !strings.Contains(extracted.Warning, "2018-06-12T00:00:00Z") || !strings.Contains(extracted.Warning, "2018-12-06T00:00:00Z") {
+		t.Errorf("Expected a warning listing both interpretations but got %s", extracted.Warning)
+	}
+}
+
+func TestThatDateOrderResolvesAmbiguity(t *testing.T) {
+	c := content{OriginalText: "Date: 12/06/18"}
+	c.prepare()
+
+	dayFirstExtractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "dateExtractor", "attributeName": "d", "dateOrder": "dayFirst"}`))
+	monthFirstExtractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "dateExtractor", "attributeName": "d", "dateOrder": "monthFirst"}`))
+	dayFirst := dayFirstExtractor(c)[0]
+	monthFirst := monthFirstExtractor(c)[0]
+
+	if dayFirst.AttributeValue != "2018-06-12T00:00:00Z" {
+		t.Errorf("Expected day first interpretation but got %s", dayFirst.AttributeValue)
+	}
+
+	if monthFirst.AttributeValue != "2018-12-06T00:00:00Z" {
+		t.Errorf("Expected month first interpretation but got %s", monthFirst.AttributeValue)
+	}
+}
+
+func TestThatConfiguredLayoutsAndLocaleAreUsed(t *testing.T) {
+	c := content{OriginalText: "Commande du 3 février 2018 à 09h30"}
+	c.prepare()
+
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "dateExtractor", "attributeName": "d", "locale": "fr", "layouts": ["2 January 2006 à 15h04"]}`))
+	extracted := extractor(c)[0]
+
+	if extracted.AttributeValue != "2018-02-03T09:30:00Z" {
+		t.Errorf("Expected french date to be parsed but got %s with warning %s", extracted.AttributeValue, extracted.Warning)
+	}
+}
+
+func TestThatMonthNamesAreOnlyTranslatedAsWholeWords(t *testing.T) {
+	translator := monthTranslators["fr"]
+
+	if translated := translator.translate("Envoyé par email le 3 MAI 2018"); translated != "Envoyé par email le 3 May 2018" {
+		t.Errorf("Expected only the month name to be translated but got %s", translated)
+	}
+
+	c := content{OriginalText: "Domain example.de, Datum 3 Mai 2018"}
+	c.prepare()
+
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "dateExtractor", "attributeName": "d", "locale": "de"}`))
+	extracted := extractor(c)[0]
+
+	if extracted.AttributeValue != "2018-05-03T00:00:00Z" {
+		t.Errorf("Expected german date to be parsed but got %s", extracted.AttributeValue)
+	}
+}
+
+func TestThatUnknownTimezoneFailsToBuildExtractor(t *testing.T) {
+	_, err := classifyAndBuildExtractor([]byte(`{"extractorType": "dateExtractor", "attributeName": "d", "timezone": "Mars/Olympus"}`))
+
+	if err == nil {
+		t.Errorf("Expected an error for an unknown timezone")
+	}
+}
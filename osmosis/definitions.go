@@ -0,0 +1,142 @@
+package osmosis
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/buger/jsonparser"
+)
+
+const referenceKey = "$ref"
+
+//definitionResolver replaces {"$ref": "name"} objects in the templates with the named entry of the top level definitions block.
+//Other keys next to $ref override the keys of the definition, so a shared extractor can be reused with a different attributeName.
+type definitionResolver struct {
+	Definitions map[string][]byte
+	Resolving   map[string]bool
+}
+
+//resolveDefinitions returns the config with every reference in its templates replaced by the definition it refers to
+func resolveDefinitions(config []byte) ([]byte, error) {
+	resolver := definitionResolver{Definitions: map[string][]byte{}, Resolving: map[string]bool{}}
+
+	jsonparser.ObjectEach(config, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+		resolver.Definitions[string(key)] = value
+		return nil
+	}, "definitions")
+
+	templates, dataType, _, err := jsonparser.Get(config, "templates")
+
+	if err != nil || dataType != jsonparser.Array {
+		return config, nil
+	}
+
+	resolvedTemplates, err := resolver.resolve(templates, jsonparser.Array)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonparser.Set(config, resolvedTemplates, "templates")
+}
+
+func (dr definitionResolver) resolve(value []byte, dataType jsonparser.ValueType) ([]byte, error) {
+	switch dataType {
+	case jsonparser.Array:
+		return dr.resolveArray(value)
+	case jsonparser.Object:
+		return dr.resolveObject(value)
+	case jsonparser.String:
+		return []byte(`"` + string(value) + `"`), nil
+	}
+
+	return value, nil
+}
+
+func (dr definitionResolver) resolveArray(value []byte) ([]byte, error) {
+	var resolveError error
+	resolvedItems := make([][]byte, 0)
+
+	jsonparser.ArrayEach(value, func(item []byte, dataType jsonparser.ValueType, offset int, err error) {
+		if resolveError != nil {
+			return
+		}
+
+		resolvedItem, err := dr.resolve(item, dataType)
+
+		if err != nil {
+			resolveError = err
+			return
+		}
+
+		resolvedItems = append(resolvedItems, resolvedItem)
+	})
+
+	if resolveError != nil {
+		return nil, resolveError
+	}
+
+	return append(append([]byte("["), bytes.Join(resolvedItems, []byte(","))...), ']'), nil
+}
+
+func (dr definitionResolver) resolveObject(value []byte) ([]byte, error) {
+	keys := make([]string, 0)
+	values := map[string][]byte{}
+
+	if reference, err := jsonparser.GetString(value, referenceKey); err == nil {
+		definition, found := dr.Definitions[reference]
+
+		if !found {
+			return nil, fmt.Errorf("ERROR: Definition %s referred to using %s is not defined", reference, referenceKey)
+		}
+
+		if dr.Resolving[reference] {
+			return nil, fmt.Errorf("ERROR: Definition %s refers to itself through a cycle of %s", reference, referenceKey)
+		}
+
+		dr.Resolving[reference] = true
+		defer delete(dr.Resolving, reference)
+
+		resolvedDefinition, err := dr.resolveObject(definition)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := dr.collectEntries(resolvedDefinition, &keys, values); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := dr.collectEntries(value, &keys, values); err != nil {
+		return nil, err
+	}
+
+	entries := make([][]byte, 0, len(keys))
+
+	for _, key := range keys {
+		if key != referenceKey {
+			entries = append(entries, append([]byte(`"`+key+`":`), values[key]...))
+		}
+	}
+
+	return append(append([]byte("{"), bytes.Join(entries, []byte(","))...), '}'), nil
+}
+
+//collectEntries resolves the entries of an object, keeping the order of keys and letting later entries override earlier ones
+func (dr definitionResolver) collectEntries(value []byte, keys *[]string, values map[string][]byte) error {
+	return jsonparser.ObjectEach(value, func(key []byte, entry []byte, dataType jsonparser.ValueType, offset int) error {
+		resolvedEntry, err := dr.resolve(entry, dataType)
+
+		if err != nil {
+			return err
+		}
+
+		if _, found := values[string(key)]; !found {
+			*keys = append(*keys, string(key))
+		}
+
+		values[string(key)] = resolvedEntry
+		return nil
+	})
+}
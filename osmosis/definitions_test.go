@@ -0,0 +1,144 @@
+package osmosis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/buger/jsonparser"
+)
+
+var definitionsConfig = `{
+	"definitions": {
+		"customerDetails": {
+			"selectorType": "textBlockSelector",
+			"fromText" : "Customer Name",
+			"toText": "Description"
+		},
+		"customerName": {
+			"extractorType": "regexExtractor",
+			"regex": "Customer Name\s+(\w+)",
+			"attributeName": "customerName",
+			"defaultValue": "NA",
+			"groupNumber": 1
+		},
+		"olaMatcher": {
+			"matcherType": "oneWordMatcher",
+			"words": "ANI Technologies"
+		},
+		"customerSection": {
+			"contentSelector": {"$ref": "customerDetails"},
+			"contentExtractors": [
+				{"$ref": "customerName"},
+				{"$ref": "customerName", "attributeName": "riderName"}
+			]
+		}
+	},
+	"templates": [
+		{
+			"templateName": "Ola",
+			"matchers": {"$ref": "olaMatcher"},
+			"sections" : [
+				{"$ref": "customerSection"}
+			]
+		}
+	]
+}`
+
+func TestThatReferencesAreReplacedByDefinitions(t *testing.T) {
+	templates, err := LoadConfig(strings.NewReader(definitionsConfig))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader(contentString))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be raised but was %s", err.Error())
+	}
+
+	values := attributeValues(keyValuePairs)
+
+	if len(keyValuePairs) != 2 || values["customerName"] != "Jacob" || values["riderName"] != "Jacob" {
+		t.Errorf("Expected referenced extractor to be used twice with an overridden attribute name but got %v", values)
+	}
+}
+
+func TestThatReferenceKeepsOrderOfDefinitionKeysAndAddsOverrides(t *testing.T) {
+	config := []byte(`{
+		"definitions": {"fee": {"extractorType": "regexExtractor", "regex": "Fee\s+(\d+)"}},
+		"templates": [{"$ref": "fee", "groupNumber": 1, "regex": "Fare\s+(\d+)"}]
+	}`)
+
+	resolved, err := resolveDefinitions(config)
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	template, _, _, _ := jsonparser.Get(resolved, "templates", "[0]")
+
+	if string(template) != `{"extractorType":"regexExtractor","regex":"Fare\s+(\d+)","groupNumber":1}` {
+		t.Errorf("Expected reference to be replaced by the definition with overrides but got %s", string(template))
+	}
+}
+
+func TestThatUnknownAndCyclicReferencesReturnError(t *testing.T) {
+	unknownConfig := `{"templates": [{"templateName": "A", "matchers": {"$ref": "missingMatcher"}}]}`
+	cyclicConfig := `{
+		"definitions": {
+			"first": {"matcherType": "conditionalMatcher", "condition": "or", "expressions": [{"$ref": "second"}]},
+			"second": {"matcherType": "conditionalMatcher", "condition": "or", "expressions": [{"$ref": "first"}]}
+		},
+		"templates": [{"templateName": "A", "matchers": {"$ref": "first"}}]
+	}`
+
+	if _, err := LoadConfig(strings.NewReader(unknownConfig)); err == nil || !strings.Contains(err.Error(), "missingMatcher") {
+		t.Errorf("Expected unknown definition to return an error but got %v", err)
+	}
+
+	if _, err := LoadConfig(strings.NewReader(cyclicConfig)); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("Expected cyclic definitions to return an error but got %v", err)
+	}
+}
+
+func TestThatDefinitionCanBeAnAliasOfAnotherDefinition(t *testing.T) {
+	config := `{
+		"definitions": {
+			"olaMatcher": {"matcherType": "oneWordMatcher", "words": "ANI Technologies"},
+			"vendorMatcher": {"$ref": "olaMatcher"},
+			"invoiceNumber": {"extractorType": "regexExtractor", "regex": "Invoice ID\s+(\w+)", "attributeName": "invoiceNumber", "groupNumber": 1},
+			"tripNumber": {"$ref": "invoiceNumber", "attributeName": "tripNumber"}
+		},
+		"templates": [{
+			"templateName": "Ola",
+			"matchers": {"$ref": "vendorMatcher"},
+			"sections": [{
+				"contentSelector": {"selectorType": "lineNumberSelector", "fromLine": 1, "toLine": 1},
+				"contentExtractors": [{"$ref": "tripNumber"}]
+			}]
+		}]
+	}`
+	templates, err := LoadConfig(strings.NewReader(config))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, _ := templates.ParseText(strings.NewReader(contentString))
+
+	if values := attributeValues(keyValuePairs); len(keyValuePairs) != 1 || values["tripNumber"] != "1IE88NHTQ55547" {
+		t.Errorf("Expected the alias to resolve to the definition it refers to but got %v", keyValuePairs)
+	}
+}
+
+func TestThatTopLevelReferenceCycleReturnsError(t *testing.T) {
+	config := `{
+		"definitions": {"a": {"$ref": "b"}, "b": {"$ref": "a"}},
+		"templates": [{"templateName": "Ola", "matchers": {"$ref": "a"}}]
+	}`
+
+	if _, err := LoadConfig(strings.NewReader(config)); err == nil || !strings.Contains(err.Error(), "refers to itself through a cycle") {
+		t.Errorf("Expected a cycle error but got %v", err)
+	}
+}
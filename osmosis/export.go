@@ -0,0 +1,147 @@
+package osmosis
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/buger/jsonparser"
+)
+
+const canonicalIndent = "    "
+
+//ToJSON returns the definitions of the loaded templates as a canonical, pretty printed JSON DSL config. Templates are ordered by name,
+//keys are sorted and strings are kept exactly as they were written, so two configs that load the same templates produce the same JSON.
+//References to definitions appear replaced by the definitions they refer to, and the config is written in the current version of the DSL.
+func (t *Templates) ToJSON() ([]byte, error) {
+	templateMap := map[string]template(*t)
+	names := make([]string, 0, len(templateMap))
+
+	for name := range templateMap {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	definitions := make([][]byte, 0, len(names))
+
+	for _, name := range names {
+		definitions = append(definitions, templateMap[name].Definition)
+	}
+
+	config := append(append([]byte(`{"templates":[`), bytes.Join(definitions, []byte(","))...), []byte(`],"version":`+strconv.Itoa(CurrentConfigVersion)+"}")...)
+	return canonicalJSON(config)
+}
+
+//canonicalJSON pretty prints a JSON DSL config with sorted keys. Unlike encoding/json it does not reject the unescaped backslashes
+//that regexes in the DSL are written with.
+func canonicalJSON(config []byte) ([]byte, error) {
+	value, dataType, _, err := jsonparser.Get(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var canonical bytes.Buffer
+
+	if err := writeCanonical(&canonical, value, dataType, 0); err != nil {
+		return nil, err
+	}
+
+	canonical.WriteString("\n")
+	return canonical.Bytes(), nil
+}
+
+func writeCanonical(buffer *bytes.Buffer, value []byte, dataType jsonparser.ValueType, depth int) error {
+	switch dataType {
+	case jsonparser.Object:
+		return writeCanonicalObject(buffer, value, depth)
+	case jsonparser.Array:
+		return writeCanonicalArray(buffer, value, depth)
+	case jsonparser.String:
+		buffer.WriteString(`"`)
+		buffer.Write(value)
+		buffer.WriteString(`"`)
+	default:
+		buffer.Write(value)
+	}
+
+	return nil
+}
+
+func writeCanonicalObject(buffer *bytes.Buffer, value []byte, depth int) error {
+	type entry struct {
+		Key      string
+		Value    []byte
+		DataType jsonparser.ValueType
+	}
+
+	entries := make([]entry, 0)
+	err := jsonparser.ObjectEach(value, func(key []byte, entryValue []byte, dataType jsonparser.ValueType, offset int) error {
+		entries = append(entries, entry{Key: string(key), Value: entryValue, DataType: dataType})
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		buffer.WriteString("{}")
+		return nil
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+
+	buffer.WriteString("{")
+
+	for index, objectEntry := range entries {
+		if index > 0 {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString("\n" + strings.Repeat(canonicalIndent, depth+1) + `"` + objectEntry.Key + `": `)
+		if err := writeCanonical(buffer, objectEntry.Value, objectEntry.DataType, depth+1); err != nil {
+			return err
+		}
+	}
+
+	buffer.WriteString("\n" + strings.Repeat(canonicalIndent, depth) + "}")
+	return nil
+}
+
+func writeCanonicalArray(buffer *bytes.Buffer, value []byte, depth int) error {
+	var itemError error
+	itemCount := 0
+
+	buffer.WriteString("[")
+
+	_, err := jsonparser.ArrayEach(value, func(item []byte, dataType jsonparser.ValueType, offset int, err error) {
+		if itemError != nil {
+			return
+		}
+		if itemCount > 0 {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString("\n" + strings.Repeat(canonicalIndent, depth+1))
+		itemError = writeCanonical(buffer, item, dataType, depth+1)
+		itemCount++
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if itemError != nil {
+		return itemError
+	}
+
+	if itemCount == 0 {
+		buffer.WriteString("]")
+		return nil
+	}
+
+	buffer.WriteString("\n" + strings.Repeat(canonicalIndent, depth) + "]")
+	return nil
+}
@@ -0,0 +1,76 @@
+package osmosis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestThatLoadedTemplatesAreWrittenAsCanonicalJSON(t *testing.T) {
+	templates, err := LoadConfig(strings.NewReader(`{"templates": [
+		{"templateName": "Uber", "matchers": {"words": "Uber", "matcherType": "oneWordMatcher"}},
+		{"templateName": "Ola", "sections": [], "matchers": {"$ref": "olaMatcher"}}
+	], "definitions": {"olaMatcher": {"matcherType": "regexMatcher", "regexExpression": "ANI\\s+Tech"}}}`))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	exported, err := templates.ToJSON()
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	expected := `{
+    "templates": [
+        {
+            "matchers": {
+                "matcherType": "regexMatcher",
+                "regexExpression": "ANI\\s+Tech"
+            },
+            "sections": [],
+            "templateName": "Ola"
+        },
+        {
+            "matchers": {
+                "matcherType": "oneWordMatcher",
+                "words": "Uber"
+            },
+            "templateName": "Uber"
+        }
+    ],
+    "version": 1
+}
+`
+
+	if string(exported) != expected {
+		t.Errorf("Expected canonical JSON\n%s\nbut got\n%s", expected, string(exported))
+	}
+}
+
+func TestThatExportedTemplatesLoadToTheSameTemplates(t *testing.T) {
+	templates, _ := LoadConfig(strings.NewReader(testConfig))
+	exported, _ := templates.ToJSON()
+
+	reloaded, err := LoadConfig(strings.NewReader(string(exported)))
+
+	if err != nil {
+		t.Fatalf("Did not expect error loading exported config. But was %s", err.Error())
+	}
+
+	reexported, _ := reloaded.ToJSON()
+
+	if string(exported) != string(reexported) {
+		t.Errorf("Expected exporting a reloaded config to be stable but got\n%s\nand\n%s", string(exported), string(reexported))
+	}
+
+	keyValuePairs, _ := reloaded.ParseText(strings.NewReader(contentString))
+
+	if len(keyValuePairs) != 1 || keyValuePairs[0].AttributeValue != "1IE88NHTQ55547" {
+		t.Errorf("Expected reloaded templates to extract like the original ones but got %v", keyValuePairs)
+	}
+
+	if !strings.Contains(string(exported), `Invoice ID\s+`) {
+		t.Errorf("Expected regexes to be kept exactly as written but got %s", string(exported))
+	}
+}
@@ -0,0 +1,838 @@
+package osmosis
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+//expression is a compiled expression. It is evaluated against the attributes extracted for a template and can only read
+//from the scope it is handed, which keeps it sandboxed from the rest of the program.
+type expression func(scope expressionScope) (interface{}, error)
+
+type expressionScope struct {
+	Attributes map[string]string
+	Tolerance  float64
+}
+
+type expressionFunction func(args []interface{}) (interface{}, error)
+
+type scopedExpressionFunction func(scope expressionScope, args []interface{}) (interface{}, error)
+
+type tokenKind int
+
+const (
+	numberToken tokenKind = iota
+	stringToken
+	identToken
+	operatorToken
+	endToken
+)
+
+type token struct {
+	Kind  tokenKind
+	Text  string
+	Index int
+}
+
+type expressionParser struct {
+	Source   string
+	Tokens   []token
+	Position int
+}
+
+var expressionFunctions = map[string]expressionFunction{
+	"concat":      concatFunction,
+	"number":      numberFunction,
+	"round":       roundFunction,
+	"abs":         absFunction,
+	"min":         minFunction,
+	"max":         maxFunction,
+	"sum":         sumFunction,
+	"trim":        trimFunction,
+	"upper":       upperFunction,
+	"lower":       lowerFunction,
+	"coalesce":    coalesceFunction,
+	"date":        dateFunction,
+	"formatDate":  formatDateFunction,
+	"addDays":     addDaysFunction,
+	"daysBetween": daysBetweenFunction,
+}
+
+//scopedExpressionFunctions can read the attributes in scope in addition to their arguments
+var scopedExpressionFunctions = map[string]scopedExpressionFunction{
+	"sumOf": sumOfFunction,
+}
+
+func compileExpression(source string) (expression, error) {
+	tokens, err := tokenizeExpression(source)
+
+	if err != nil {
+		return nil, err
+	}
+
+	parser := expressionParser{Source: source, Tokens: tokens}
+	compiled, err := parser.parseTernary()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if parser.peek().Kind != endToken {
+		return nil, fmt.Errorf("ERROR: Unexpected token %s at position %d in expression %s", parser.peek().Text, parser.peek().Index, source)
+	}
+
+	return compiled, nil
+}
+
+func tokenizeExpression(source string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(source)
+	twoCharOperators := []string{"==", "!=", "<=", ">=", "&&", "||"}
+
+	for index := 0; index < len(runes); {
+		current := runes[index]
+
+		if unicode.IsSpace(current) {
+			index++
+			continue
+		}
+
+		if unicode.IsDigit(current) || (current == '.' && index+1 < len(runes) && unicode.IsDigit(runes[index+1])) {
+			start := index
+			for index < len(runes) && (unicode.IsDigit(runes[index]) || runes[index] == '.') {
+				index++
+			}
+			tokens = append(tokens, token{Kind: numberToken, Text: string(runes[start:index]), Index: start})
+			continue
+		}
+
+		if unicode.IsLetter(current) || current == '_' {
+			start := index
+			for index < len(runes) && (unicode.IsLetter(runes[index]) || unicode.IsDigit(runes[index]) || runes[index] == '_' || runes[index] == '.') {
+				index++
+			}
+			tokens = append(tokens, token{Kind: identToken, Text: string(runes[start:index]), Index: start})
+			continue
+		}
+
+		if current == '"' || current == '\'' {
+			start := index
+			var literal strings.Builder
+			index++
+			for index < len(runes) && runes[index] != current {
+				if runes[index] == '\\' && index+1 < len(runes) {
+					index++
+				}
+				literal.WriteRune(runes[index])
+				index++
+			}
+			if index >= len(runes) {
+				return nil, fmt.Errorf("ERROR: Unterminated string starting at position %d in expression %s", start, source)
+			}
+			index++
+			tokens = append(tokens, token{Kind: stringToken, Text: literal.String(), Index: start})
+			continue
+		}
+
+		if index+1 < len(runes) {
+			pair := string(runes[index : index+2])
+			isPair := false
+			for _, operator := range twoCharOperators {
+				if pair == operator {
+					isPair = true
+				}
+			}
+			if isPair {
+				tokens = append(tokens, token{Kind: operatorToken, Text: pair, Index: index})
+				index += 2
+				continue
+			}
+		}
+
+		if strings.ContainsRune("+-*/%<>!?:(),", current) {
+			tokens = append(tokens, token{Kind: operatorToken, Text: string(current), Index: index})
+			index++
+			continue
+		}
+
+		return nil, fmt.Errorf("ERROR: Unexpected character %q at position %d in expression %s", current, index, source)
+	}
+
+	return append(tokens, token{Kind: endToken, Index: len(runes)}), nil
+}
+
+func (p *expressionParser) peek() token {
+	return p.Tokens[p.Position]
+}
+
+func (p *expressionParser) accept(operators ...string) (string, bool) {
+	current := p.peek()
+
+	if current.Kind != operatorToken {
+		return "", false
+	}
+
+	for _, operator := range operators {
+		if current.Text == operator {
+			p.Position++
+			return operator, true
+		}
+	}
+
+	return "", false
+}
+
+func (p *expressionParser) expect(operator string) error {
+	if _, ok := p.accept(operator); !ok {
+		return fmt.Errorf("ERROR: Expected %s at position %d in expression %s", operator, p.peek().Index, p.Source)
+	}
+	return nil
+}
+
+func (p *expressionParser) parseTernary() (expression, error) {
+	condition, err := p.parseBinary(0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := p.accept("?"); !ok {
+		return condition, nil
+	}
+
+	whenTrue, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.expect(":"); err != nil {
+		return nil, err
+	}
+
+	whenFalse, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(scope expressionScope) (interface{}, error) {
+		result, err := condition(scope)
+		if err != nil {
+			return nil, err
+		}
+
+		isTrue, err := asBool(result)
+		if err != nil {
+			return nil, err
+		}
+
+		if isTrue {
+			return whenTrue(scope)
+		}
+		return whenFalse(scope)
+	}, nil
+}
+
+//binaryPrecedence lists the binary operators from the loosest to the tightest binding level
+var binaryPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *expressionParser) parseBinary(level int) (expression, error) {
+	if level >= len(binaryPrecedence) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		operator, ok := p.accept(binaryPrecedence[level]...)
+
+		if !ok {
+			return left, nil
+		}
+
+		right, err := p.parseBinary(level + 1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		left = binaryExpression(operator, left, right)
+	}
+}
+
+func (p *expressionParser) parseUnary() (expression, error) {
+	operator, ok := p.accept("!", "-")
+
+	if !ok {
+		return p.parsePrimary()
+	}
+
+	operand, err := p.parseUnary()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func(scope expressionScope) (interface{}, error) {
+		value, err := operand(scope)
+		if err != nil {
+			return nil, err
+		}
+
+		if operator == "!" {
+			isTrue, err := asBool(value)
+			return !isTrue, err
+		}
+
+		number, err := asNumber(value)
+		return -number, err
+	}, nil
+}
+
+func (p *expressionParser) parsePrimary() (expression, error) {
+	current := p.peek()
+
+	switch current.Kind {
+	case numberToken:
+		p.Position++
+		number, err := strconv.ParseFloat(current.Text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("ERROR: Invalid number %s in expression %s", current.Text, p.Source)
+		}
+		return constantExpression(number), nil
+	case stringToken:
+		p.Position++
+		return constantExpression(current.Text), nil
+	case identToken:
+		p.Position++
+		if current.Text == "true" || current.Text == "false" {
+			return constantExpression(current.Text == "true"), nil
+		}
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(current)
+		}
+		return attributeExpression(current.Text), nil
+	}
+
+	if _, ok := p.accept("("); ok {
+		inner, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	}
+
+	return nil, fmt.Errorf("ERROR: Unexpected token %s at position %d in expression %s", current.Text, current.Index, p.Source)
+}
+
+func (p *expressionParser) parseCall(name token) (expression, error) {
+	function, found := expressionFunctions[name.Text]
+	scopedFunction, isScoped := scopedExpressionFunctions[name.Text]
+
+	if !found && !isScoped && name.Text != "if" {
+		return nil, fmt.Errorf("ERROR: Unknown function %s in expression %s", name.Text, p.Source)
+	}
+
+	args := make([]expression, 0)
+
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+
+			if _, ok := p.accept(","); ok {
+				continue
+			}
+			if err = p.expect(")"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	if name.Text == "if" {
+		return conditionalCallExpression(args, p.Source)
+	}
+
+	return func(scope expressionScope) (interface{}, error) {
+		values := make([]interface{}, 0, len(args))
+		for _, arg := range args {
+			value, err := arg(scope)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+
+		var result interface{}
+		var err error
+		if isScoped {
+			result, err = scopedFunction(scope, values)
+		} else {
+			result, err = function(values)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ERROR: Function %s failed. Error is %s", name.Text, err.Error())
+		}
+		return result, nil
+	}, nil
+}
+
+//conditionalCallExpression builds if(condition, whenTrue, whenFalse). Only the chosen branch is evaluated.
+func conditionalCallExpression(args []expression, source string) (expression, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("ERROR: Function if expects 3 arguments in expression %s", source)
+	}
+
+	return func(scope expressionScope) (interface{}, error) {
+		result, err := args[0](scope)
+		if err != nil {
+			return nil, err
+		}
+
+		isTrue, err := asBool(result)
+		if err != nil {
+			return nil, err
+		}
+
+		if isTrue {
+			return args[1](scope)
+		}
+		return args[2](scope)
+	}, nil
+}
+
+func constantExpression(value interface{}) expression {
+	return func(scope expressionScope) (interface{}, error) {
+		return value, nil
+	}
+}
+
+func attributeExpression(name string) expression {
+	return func(scope expressionScope) (interface{}, error) {
+		value, found := scope.Attributes[name]
+		if !found {
+			return nil, fmt.Errorf("ERROR: Attribute %s is not available", name)
+		}
+		return value, nil
+	}
+}
+
+func binaryExpression(operator string, left, right expression) expression {
+	return func(scope expressionScope) (interface{}, error) {
+		leftValue, err := left(scope)
+		if err != nil {
+			return nil, err
+		}
+
+		if operator == "&&" || operator == "||" {
+			return logicalResult(operator, leftValue, right, scope)
+		}
+
+		rightValue, err := right(scope)
+		if err != nil {
+			return nil, err
+		}
+
+		switch operator {
+		case "==", "!=":
+			isEqual := valuesEqual(leftValue, rightValue, scope.Tolerance)
+			return isEqual == (operator == "=="), nil
+		case "<", "<=", ">", ">=":
+			return compareValues(operator, leftValue, rightValue)
+		case "+":
+			return addValues(leftValue, rightValue)
+		}
+
+		leftNumber, err := asNumber(leftValue)
+		if err != nil {
+			return nil, err
+		}
+
+		rightNumber, err := asNumber(rightValue)
+		if err != nil {
+			return nil, err
+		}
+
+		switch operator {
+		case "-":
+			return leftNumber - rightNumber, nil
+		case "*":
+			return leftNumber * rightNumber, nil
+		case "/":
+			if rightNumber == 0 {
+				return nil, fmt.Errorf("ERROR: Division by zero")
+			}
+			return leftNumber / rightNumber, nil
+		}
+
+		if rightNumber == 0 {
+			return nil, fmt.Errorf("ERROR: Division by zero")
+		}
+		return math.Mod(leftNumber, rightNumber), nil
+	}
+}
+
+func logicalResult(operator string, leftValue interface{}, right expression, scope expressionScope) (interface{}, error) {
+	leftTrue, err := asBool(leftValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if operator == "&&" && !leftTrue {
+		return false, nil
+	}
+
+	if operator == "||" && leftTrue {
+		return true, nil
+	}
+
+	rightValue, err := right(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return asBool(rightValue)
+}
+
+//addValues adds two values when both are numeric and concatenates them otherwise
+func addValues(left, right interface{}) (interface{}, error) {
+	leftNumber, leftErr := asNumber(left)
+	rightNumber, rightErr := asNumber(right)
+
+	if leftErr == nil && rightErr == nil {
+		return leftNumber + rightNumber, nil
+	}
+
+	return formatValue(left) + formatValue(right), nil
+}
+
+//valuesEqual compares numbers within the tolerance of the scope, which allows for rounding in printed amounts
+func valuesEqual(left, right interface{}, tolerance float64) bool {
+	leftTime, leftIsTime := left.(time.Time)
+	rightTime, rightIsTime := right.(time.Time)
+
+	if leftIsTime && rightIsTime {
+		return leftTime.Equal(rightTime)
+	}
+
+	leftNumber, leftErr := asNumber(left)
+	rightNumber, rightErr := asNumber(right)
+
+	if leftErr == nil && rightErr == nil {
+		return math.Abs(leftNumber-rightNumber) <= tolerance
+	}
+
+	return formatValue(left) == formatValue(right)
+}
+
+func compareValues(operator string, left, right interface{}) (interface{}, error) {
+	var comparison int
+	leftTime, leftIsTime := left.(time.Time)
+	rightTime, rightIsTime := right.(time.Time)
+	leftNumber, leftErr := asNumber(left)
+	rightNumber, rightErr := asNumber(right)
+
+	if leftIsTime && rightIsTime {
+		comparison = int(leftTime.Sub(rightTime))
+	} else if leftErr == nil && rightErr == nil {
+		comparison = int(math.Copysign(1, leftNumber-rightNumber))
+		if leftNumber == rightNumber {
+			comparison = 0
+		}
+	} else {
+		comparison = strings.Compare(formatValue(left), formatValue(right))
+	}
+
+	switch operator {
+	case "<":
+		return comparison < 0, nil
+	case "<=":
+		return comparison <= 0, nil
+	case ">":
+		return comparison > 0, nil
+	}
+	return comparison >= 0, nil
+}
+
+func asNumber(value interface{}) (float64, error) {
+	switch typed := value.(type) {
+	case float64:
+		return typed, nil
+	case string:
+		cleaned := strings.Replace(strings.TrimSpace(typed), ",", "", -1)
+		number, err := strconv.ParseFloat(cleaned, 64)
+		if err != nil {
+			return 0, fmt.Errorf("ERROR: Value %q is not a number", typed)
+		}
+		return number, nil
+	}
+
+	return 0, fmt.Errorf("ERROR: Value %s is not a number", formatValue(value))
+}
+
+func asBool(value interface{}) (bool, error) {
+	switch typed := value.(type) {
+	case bool:
+		return typed, nil
+	case string:
+		return strconv.ParseBool(strings.TrimSpace(typed))
+	}
+
+	return false, fmt.Errorf("ERROR: Value %s is not a boolean", formatValue(value))
+}
+
+func asTime(value interface{}) (time.Time, error) {
+	switch typed := value.(type) {
+	case time.Time:
+		return typed, nil
+	case string:
+		return time.Parse(time.RFC3339, strings.TrimSpace(typed))
+	}
+
+	return time.Time{}, fmt.Errorf("ERROR: Value %s is not a date", formatValue(value))
+}
+
+//formatValue converts an evaluated value to the string form stored in ExtractedContent
+func formatValue(value interface{}) string {
+	switch typed := value.(type) {
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(typed)
+	case time.Time:
+		return typed.Format(time.RFC3339)
+	case string:
+		return typed
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+func numbersOf(args []interface{}) ([]float64, error) {
+	numbers := make([]float64, 0, len(args))
+
+	for _, arg := range args {
+		number, err := asNumber(arg)
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, number)
+	}
+
+	return numbers, nil
+}
+
+func expectArgs(args []interface{}, count int) error {
+	if len(args) != count {
+		return fmt.Errorf("ERROR: Expected %d arguments but got %d", count, len(args))
+	}
+	return nil
+}
+
+func concatFunction(args []interface{}) (interface{}, error) {
+	var joined strings.Builder
+
+	for _, arg := range args {
+		joined.WriteString(formatValue(arg))
+	}
+
+	return joined.String(), nil
+}
+
+func numberFunction(args []interface{}) (interface{}, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return nil, err
+	}
+	return asNumber(args[0])
+}
+
+func roundFunction(args []interface{}) (interface{}, error) {
+	if err := expectArgs(args, 2); err != nil {
+		return nil, err
+	}
+
+	numbers, err := numbersOf(args)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := math.Pow(10, numbers[1])
+	return math.Round(numbers[0]*scale) / scale, nil
+}
+
+func absFunction(args []interface{}) (interface{}, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return nil, err
+	}
+
+	number, err := asNumber(args[0])
+	return math.Abs(number), err
+}
+
+func minFunction(args []interface{}) (interface{}, error) {
+	numbers, err := numbersOf(args)
+	if err != nil || len(numbers) == 0 {
+		return nil, fmt.Errorf("ERROR: min expects at least one number")
+	}
+
+	result := numbers[0]
+	for _, number := range numbers[1:] {
+		result = math.Min(result, number)
+	}
+	return result, nil
+}
+
+func maxFunction(args []interface{}) (interface{}, error) {
+	numbers, err := numbersOf(args)
+	if err != nil || len(numbers) == 0 {
+		return nil, fmt.Errorf("ERROR: max expects at least one number")
+	}
+
+	result := numbers[0]
+	for _, number := range numbers[1:] {
+		result = math.Max(result, number)
+	}
+	return result, nil
+}
+
+func sumFunction(args []interface{}) (interface{}, error) {
+	numbers, err := numbersOf(args)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0.0
+	for _, number := range numbers {
+		total += number
+	}
+	return total, nil
+}
+
+//sumOfFunction adds up all attributes whose names match a glob pattern, e.g. sumOf("item*Amount")
+func sumOfFunction(scope expressionScope, args []interface{}) (interface{}, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return nil, err
+	}
+
+	total := 0.0
+	pattern := formatValue(args[0])
+
+	for name, value := range scope.Attributes {
+		if isMatch, err := path.Match(pattern, name); err != nil {
+			return nil, err
+		} else if !isMatch {
+			continue
+		}
+
+		number, err := asNumber(value)
+		if err != nil {
+			return nil, fmt.Errorf("ERROR: Attribute %s is not a number", name)
+		}
+		total += number
+	}
+
+	return total, nil
+}
+
+func trimFunction(args []interface{}) (interface{}, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return nil, err
+	}
+	return strings.TrimSpace(formatValue(args[0])), nil
+}
+
+func upperFunction(args []interface{}) (interface{}, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return nil, err
+	}
+	return strings.ToUpper(formatValue(args[0])), nil
+}
+
+func lowerFunction(args []interface{}) (interface{}, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return nil, err
+	}
+	return strings.ToLower(formatValue(args[0])), nil
+}
+
+func coalesceFunction(args []interface{}) (interface{}, error) {
+	for _, arg := range args {
+		if strings.TrimSpace(formatValue(arg)) != "" {
+			return arg, nil
+		}
+	}
+	return "", nil
+}
+
+//dateFunction parses a value using a go time layout, e.g. date(invoiceDate, "Jan 2, 2006")
+func dateFunction(args []interface{}) (interface{}, error) {
+	if err := expectArgs(args, 2); err != nil {
+		return nil, err
+	}
+	return time.Parse(formatValue(args[1]), strings.TrimSpace(formatValue(args[0])))
+}
+
+func formatDateFunction(args []interface{}) (interface{}, error) {
+	if err := expectArgs(args, 2); err != nil {
+		return nil, err
+	}
+
+	date, err := asTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return date.Format(formatValue(args[1])), nil
+}
+
+func addDaysFunction(args []interface{}) (interface{}, error) {
+	if err := expectArgs(args, 2); err != nil {
+		return nil, err
+	}
+
+	date, err := asTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := asNumber(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return date.AddDate(0, 0, int(days)), nil
+}
+
+func daysBetweenFunction(args []interface{}) (interface{}, error) {
+	if err := expectArgs(args, 2); err != nil {
+		return nil, err
+	}
+
+	from, err := asTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := asTime(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return math.Floor(to.Sub(from).Hours() / 24), nil
+}
@@ -0,0 +1,88 @@
+package osmosis
+
+import (
+	"strings"
+	"testing"
+)
+
+func evaluateForTest(t *testing.T, source string, attributes map[string]string) string {
+	compiled, err := compileExpression(source)
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned while compiling %s. But was %s", source, err.Error())
+	}
+
+	result, err := compiled(expressionScope{Attributes: attributes})
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned while evaluating %s. But was %s", source, err.Error())
+	}
+
+	return formatValue(result)
+}
+
+func TestThatArithmeticExpressionIsEvaluatedOverAttributes(t *testing.T) {
+	attributes := map[string]string{"tripFare": "250.50", "discount": "50.25", "tax": "1,000"}
+
+	if result := evaluateForTest(t, "tripFare - discount", attributes); result != "200.25" {
+		t.Errorf("Expected tripFare - discount to be 200.25 but was %s", result)
+	}
+
+	if result := evaluateForTest(t, "(tripFare - discount) * 2 + tax / 10", attributes); result != "500.5" {
+		t.Errorf("Expected arithmetic with precedence to be 500.5 but was %s", result)
+	}
+}
+
+func TestThatPlusConcatenatesWhenValuesAreNotNumeric(t *testing.T) {
+	attributes := map[string]string{"line1": "Rose block", "line2": "Road 9"}
+
+	if result := evaluateForTest(t, "line1 + ', ' + line2", attributes); result != "Rose block, Road 9" {
+		t.Errorf("Expected concatenated address but was %s", result)
+	}
+
+	if result := evaluateForTest(t, "concat(upper(line2), '-', 1)", attributes); result != "ROAD 9-1" {
+		t.Errorf("Expected concat to join values but was %s", result)
+	}
+}
+
+func TestThatConditionalsAreEvaluated(t *testing.T) {
+	attributes := map[string]string{"total": "147.0", "paid": "Paid"}
+
+	if result := evaluateForTest(t, "total > 100 && paid == 'Paid' ? 'large' : 'small'", attributes); result != "large" {
+		t.Errorf("Expected ternary to pick large but was %s", result)
+	}
+
+	if result := evaluateForTest(t, "if(total <= 100, 'small', 'large')", attributes); result != "large" {
+		t.Errorf("Expected if function to pick large but was %s", result)
+	}
+}
+
+func TestThatDateMathIsSupported(t *testing.T) {
+	attributes := map[string]string{"orderDate": "16 May 2018"}
+
+	if result := evaluateForTest(t, "addDays(date(orderDate, '2 Jan 2006'), 30)", attributes); result != "2018-06-15T00:00:00Z" {
+		t.Errorf("Expected due date to be 2018-06-15T00:00:00Z but was %s", result)
+	}
+
+	if result := evaluateForTest(t, "daysBetween(date(orderDate, '2 Jan 2006'), date('1 Jun 2018', '2 Jan 2006'))", attributes); result != "16" {
+		t.Errorf("Expected 16 days between dates but was %s", result)
+	}
+}
+
+func TestThatMissingAttributeIsReportedAsError(t *testing.T) {
+	compiled, _ := compileExpression("total + 1")
+
+	_, err := compiled(expressionScope{Attributes: map[string]string{}})
+
+	if err == nil || !strings.Contains(err.Error(), "total") {
+		t.Errorf("Expected error for missing attribute total")
+	}
+}
+
+func TestThatInvalidExpressionDoesNotCompile(t *testing.T) {
+	for _, source := range []string{"total +", "unknownFunction(1)", "(total", "'open"} {
+		if _, err := compileExpression(source); err == nil {
+			t.Errorf("Expected expression %s to fail compilation", source)
+		}
+	}
+}
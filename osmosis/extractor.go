@@ -8,7 +8,7 @@ import (
 	"github.com/buger/jsonparser"
 )
 
-type contentExtractor func(c content) ExtractedContent
+type contentExtractor func(c content) []ExtractedContent
 
 type regexExtractor struct {
 	Regex         string
@@ -26,6 +26,24 @@ func classifyAndBuildExtractor(value []byte) (contentExtractor, error) {
 
 	if strings.EqualFold(extractorType, "regexExtractor") {
 		return getRegexExtractor(value).asContentExtractor()
+	} else if strings.EqualFold(extractorType, "gstinExtractor") {
+		return getIdentifierExtractor(value, gstinIdentifier).asContentExtractor()
+	} else if strings.EqualFold(extractorType, "panExtractor") {
+		return getIdentifierExtractor(value, panIdentifier).asContentExtractor()
+	} else if strings.EqualFold(extractorType, "cinExtractor") {
+		return getIdentifierExtractor(value, cinIdentifier).asContentExtractor()
+	} else if strings.EqualFold(extractorType, "ifscExtractor") {
+		return getIdentifierExtractor(value, ifscIdentifier).asContentExtractor()
+	} else if strings.EqualFold(extractorType, "mobileNumberExtractor") {
+		return getIdentifierExtractor(value, mobileNumberIdentifier).asContentExtractor()
+	} else if strings.EqualFold(extractorType, "dateExtractor") {
+		return getDateExtractor(value).asContentExtractor()
+	} else if strings.EqualFold(extractorType, "labelExtractor") {
+		return getLabelExtractor(value).asContentExtractor()
+	} else if strings.EqualFold(extractorType, "autoKeyValueExtractor") {
+		return getAutoKeyValueExtractor(value).asContentExtractor()
+	} else if strings.EqualFold(extractorType, "tableExtractor") {
+		return getTableExtractor(value).asContentExtractor()
 	}
 
 	return nil, fmt.Errorf("ERROR: Unknown extractor type %s", extractorType)
@@ -51,22 +69,27 @@ func (re regexExtractor) asContentExtractor() (contentExtractor, error) {
 		return nil, fmt.Errorf("ERROR: Could not compile the extractor regex %s", re.Regex)
 	}
 
-	return func(c content) ExtractedContent {
+	return func(c content) []ExtractedContent {
 
 		extractedKeyVal := ExtractedContent{
 			AttributeName:  re.AttributeName,
 			AttributeValue: re.DefaultValue,
 		}
 
-		result := compiledRegex.FindStringSubmatch(c.OriginalText)
+		result := compiledRegex.FindStringSubmatchIndex(c.OriginalText)
 
-		for k, val := range result {
+		for k := 0; k < len(result)/2; k++ {
 			if int64(k) == re.GroupNumber {
-				extractedKeyVal.AttributeValue = strings.TrimSpace(val)
-				return extractedKeyVal
+				start, end := result[2*k], result[2*k+1]
+				extractedKeyVal.AttributeValue = ""
+				if start >= 0 {
+					extractedKeyVal.AttributeValue = strings.TrimSpace(c.OriginalText[start:end])
+					extractedKeyVal.Page = c.pageAt(start)
+				}
+				return []ExtractedContent{extractedKeyVal}
 			}
 		}
 
-		return extractedKeyVal
+		return []ExtractedContent{extractedKeyVal}
 	}, nil
 }
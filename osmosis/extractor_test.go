@@ -24,7 +24,7 @@ func TestThatRegexExtractorIsUsedToExtractTheValue(t *testing.T) {
 		t.Errorf("Did not expect error to be returned. But was %s", err.Error())
 	}
 
-	extractedContent := extractor(c)
+	extractedContent := extractor(c)[0]
 
 	if strings.Compare(extractedContent.AttributeName, expectedAttributeName) != 0 {
 		t.Errorf("Expected attribute name [%s] to match [%s]", extractedContent.AttributeName, expectedAttributeName)
@@ -0,0 +1,183 @@
+package osmosis
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buger/jsonparser"
+)
+
+const base36Characters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+type identifierKind struct {
+	Name      string
+	Candidate *regexp.Regexp
+	Normalize func(candidate string) string
+	Validate  func(normalized string, validateChecksum bool) bool
+}
+
+type identifierExtractor struct {
+	Kind             identifierKind
+	AttributeName    string
+	DefaultValue     string
+	ValidateChecksum bool
+}
+
+var gstinIdentifier = identifierKind{
+	Name:      "GSTIN",
+	Candidate: regexp.MustCompile(`(?i)\b[0-9]{2}[A-Z]{5}[0-9]{4}[A-Z][A-Z0-9]{3}\b`),
+	Normalize: strings.ToUpper,
+	Validate:  isValidGSTIN,
+}
+
+var panIdentifier = identifierKind{
+	Name:      "PAN",
+	Candidate: regexp.MustCompile(`(?i)\b[A-Z]{5}[0-9]{4}[A-Z]\b`),
+	Normalize: strings.ToUpper,
+	Validate: func(normalized string, validateChecksum bool) bool {
+		return isValidPAN(normalized)
+	},
+}
+
+var cinIdentifier = identifierKind{
+	Name:      "CIN",
+	Candidate: regexp.MustCompile(`(?i)\b[LU][0-9]{5}[A-Z]{2}[0-9]{4}[A-Z]{3}[0-9]{6}\b`),
+	Normalize: strings.ToUpper,
+	Validate: func(normalized string, validateChecksum bool) bool {
+		return isValidCIN(normalized)
+	},
+}
+
+var ifscIdentifier = identifierKind{
+	Name:      "IFSC",
+	Candidate: regexp.MustCompile(`(?i)\b[A-Z]{4}0[A-Z0-9]{6}\b`),
+	Normalize: strings.ToUpper,
+	Validate: func(normalized string, validateChecksum bool) bool {
+		return true
+	},
+}
+
+var mobileNumberIdentifier = identifierKind{
+	Name:      "Mobile number",
+	Candidate: regexp.MustCompile(`(?:\+91[\s-]?|\b91[\s-]?|\b0|\b)[6-9][0-9]{4}[\s-]?[0-9]{5}\b`),
+	Normalize: normalizeMobileNumber,
+	Validate: func(normalized string, validateChecksum bool) bool {
+		return len(normalized) == 13
+	},
+}
+
+//stateCodes are the two letter state and union territory codes used in a CIN
+var stateCodes = strings.Split("AN,AP,AR,AS,BR,CH,CT,DD,DL,DN,GA,GJ,HP,HR,JH,JK,KA,KL,LA,LD,MH,ML,MN,MP,MZ,NL,OR,PB,PY,RJ,SK,TG,TN,TR,UP,UR,UT,WB", ",")
+
+//companyClassCodes are the ownership codes used in a CIN, e.g. PTC for a private limited company
+var companyClassCodes = strings.Split("FLC,FTC,GAP,GAT,GOI,NPL,OPC,PLC,PTC,SGC,ULL,ULT", ",")
+
+func getIdentifierExtractor(value []byte, kind identifierKind) identifierExtractor {
+	attributeName, _ := jsonparser.GetString(value, "attributeName")
+	defaultValue, _ := jsonparser.GetString(value, "defaultValue")
+	validateChecksum, err := jsonparser.GetBoolean(value, "validateChecksum")
+
+	if err != nil {
+		validateChecksum = true
+	}
+
+	return identifierExtractor{
+		Kind:             kind,
+		AttributeName:    attributeName,
+		DefaultValue:     defaultValue,
+		ValidateChecksum: validateChecksum,
+	}
+}
+
+func (ie identifierExtractor) asContentExtractor() (contentExtractor, error) {
+	if ie.AttributeName == "" {
+		return nil, fmt.Errorf("ERROR: %s extractor requires an attributeName", ie.Kind.Name)
+	}
+
+	return func(c content) []ExtractedContent {
+		extractedKeyVal := ExtractedContent{
+			AttributeName:  ie.AttributeName,
+			AttributeValue: ie.DefaultValue,
+		}
+
+		for _, candidate := range ie.Kind.Candidate.FindAllString(c.OriginalText, -1) {
+			normalized := ie.Kind.Normalize(candidate)
+
+			if ie.Kind.Validate(normalized, ie.ValidateChecksum) {
+				extractedKeyVal.AttributeValue = normalized
+				return []ExtractedContent{extractedKeyVal}
+			}
+		}
+
+		return []ExtractedContent{extractedKeyVal}
+	}, nil
+}
+
+//isValidGSTIN checks the state code, the embedded PAN and the base 36 check character of a GSTIN
+func isValidGSTIN(gstin string, validateChecksum bool) bool {
+	stateCode, err := strconv.Atoi(gstin[:2])
+
+	if err != nil || stateCode < 1 || (stateCode > 38 && stateCode != 97 && stateCode != 99) {
+		return false
+	}
+
+	if !isValidPAN(gstin[2:12]) || gstin[12] == '0' {
+		return false
+	}
+
+	if !validateChecksum {
+		return true
+	}
+
+	sum := 0
+	for index := 0; index < 14; index++ {
+		product := strings.IndexByte(base36Characters, gstin[index]) * (index%2 + 1)
+		sum += product/36 + product%36
+	}
+
+	return base36Characters[(36-sum%36)%36] == gstin[14]
+}
+
+//isValidPAN checks that the fourth character of a PAN is a known holder type
+func isValidPAN(pan string) bool {
+	return strings.ContainsRune("ABCFGHJLPT", rune(pan[3]))
+}
+
+//isValidCIN checks the state code, year of incorporation and ownership class of a CIN
+func isValidCIN(cin string) bool {
+	year, err := strconv.Atoi(cin[8:12])
+
+	if err != nil || year < 1850 || year > time.Now().Year() {
+		return false
+	}
+
+	return containsCode(stateCodes, cin[6:8]) && containsCode(companyClassCodes, cin[12:15])
+}
+
+func containsCode(codes []string, code string) bool {
+	for _, knownCode := range codes {
+		if knownCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+//normalizeMobileNumber strips separators and prefixes from a number and returns it in +91XXXXXXXXXX format
+func normalizeMobileNumber(candidate string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, candidate)
+
+	if len(digits) > 10 {
+		digits = digits[len(digits)-10:]
+	}
+
+	return "+91" + digits
+}
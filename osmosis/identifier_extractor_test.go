@@ -0,0 +1,75 @@
+package osmosis
+
+import (
+	"testing"
+)
+
+var identifierText = `GST IN: 29aaacb5343e1z3, 29BBZZF8899Q0ZQ
+Reg Name: Food Vista India Pvt. Ltd. CIN: U15209KA2014PTC075887 PAN: AACCF4496Q
+Supplier GSTIN 27AAPFU0939F1ZV, Bank IFSC HDFC0001234
+Phone: +91 98765-43210, Alt 0 12345 67890`
+
+func TestThatGSTINExtractorSkipsCandidatesWithInvalidChecksum(t *testing.T) {
+	c := content{OriginalText: identifierText}
+	c.prepare()
+
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "gstinExtractor", "attributeName": "gstin", "defaultValue": "NA"}`))
+	value := extractor(c)[0].AttributeValue
+
+	if value != "27AAPFU0939F1ZV" {
+		t.Errorf("Expected GSTIN with valid checksum 27AAPFU0939F1ZV but got %s", value)
+	}
+}
+
+func TestThatGSTINExtractorNormalizesWhenChecksumValidationIsDisabled(t *testing.T) {
+	c := content{OriginalText: identifierText}
+	c.prepare()
+
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "gstinExtractor", "attributeName": "gstin", "validateChecksum": false}`))
+	value := extractor(c)[0].AttributeValue
+
+	if value != "29AAACB5343E1Z3" {
+		t.Errorf("Expected first structurally valid GSTIN 29AAACB5343E1Z3 but got %s", value)
+	}
+}
+
+func TestThatPanCinAndIfscExtractorsReturnValidatedValues(t *testing.T) {
+	expectations := map[string]string{
+		`{"extractorType": "panExtractor", "attributeName": "pan"}`:   "AACCF4496Q",
+		`{"extractorType": "cinExtractor", "attributeName": "cin"}`:   "U15209KA2014PTC075887",
+		`{"extractorType": "ifscExtractor", "attributeName": "ifsc"}`: "HDFC0001234",
+	}
+
+	c := content{OriginalText: identifierText}
+	c.prepare()
+
+	for config, expected := range expectations {
+		extractor, _ := classifyAndBuildExtractor([]byte(config))
+
+		if value := extractor(c)[0].AttributeValue; value != expected {
+			t.Errorf("Expected %s but got %s for %s", expected, value, config)
+		}
+	}
+}
+
+func TestThatMobileNumberExtractorNormalizesToCountryCodeFormat(t *testing.T) {
+	c := content{OriginalText: identifierText}
+	c.prepare()
+
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "mobileNumberExtractor", "attributeName": "phone"}`))
+	value := extractor(c)[0].AttributeValue
+
+	if value != "+919876543210" {
+		t.Errorf("Expected mobile number +919876543210 but got %s", value)
+	}
+}
+
+func TestThatIdentifierExtractorReturnsDefaultValueWhenNothingIsValid(t *testing.T) {
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "cinExtractor", "attributeName": "cin", "defaultValue": "NA"}`))
+	c := content{OriginalText: "CIN: U15209XX2014PTC075887"}
+	c.prepare()
+
+	if value := extractor(c)[0].AttributeValue; value != "NA" {
+		t.Errorf("Expected default value NA for CIN with unknown state code but got %s", value)
+	}
+}
@@ -0,0 +1,142 @@
+package osmosis
+
+import (
+	"fmt"
+
+	"github.com/buger/jsonparser"
+)
+
+//templateResolver builds templates in an order where every template is built after the template it extends
+type templateResolver struct {
+	Definitions map[string][]byte
+	Built       map[string]template
+	Resolving   map[string]bool
+}
+
+func newTemplateResolver() *templateResolver {
+	return &templateResolver{
+		Definitions: map[string][]byte{},
+		Built:       map[string]template{},
+		Resolving:   map[string]bool{},
+	}
+}
+
+func (tr *templateResolver) resolve(templateName string) (template, error) {
+	if builtTemplate, found := tr.Built[templateName]; found {
+		return builtTemplate, nil
+	}
+
+	if tr.Resolving[templateName] {
+		return template{}, fmt.Errorf("ERROR: Template %s extends itself through a cycle of extends", templateName)
+	}
+
+	tr.Resolving[templateName] = true
+	defer delete(tr.Resolving, templateName)
+
+	templateDef := tr.Definitions[templateName]
+	var baseTemplate *template
+
+	if baseName, err := jsonparser.GetString(templateDef, "extends"); err == nil {
+		if _, found := tr.Definitions[baseName]; !found {
+			return template{}, fmt.Errorf("ERROR: Template %s extends %s which is not defined", templateName, baseName)
+		}
+
+		resolvedBase, err := tr.resolve(baseName)
+
+		if err != nil {
+			return template{}, err
+		}
+
+		baseTemplate = &resolvedBase
+	}
+
+	builtTemplate, err := parseTemplate(templateDef, baseTemplate)
+
+	if err != nil {
+		return template{}, err
+	}
+
+	tr.Built[templateName] = builtTemplate
+	return builtTemplate, nil
+}
+
+//inherit fills in what the template does not configure from the template it extends. Sections with an id replace the base section with
+//the same id in place, other sections are appended. Computed attributes and assertions are merged by name in the same way.
+func (t template) inherit(base template, hasMatcher bool, hasPageFurniture bool) template {
+	if !hasMatcher {
+		t.Matcher = base.Matcher
+	}
+
+	if !hasPageFurniture {
+		t.PageFurniture = base.PageFurniture
+	}
+
+	t.Sections = mergeSections(base.Sections, t.Sections)
+	t.ComputedAttributes = mergeComputedAttributes(base.ComputedAttributes, t.ComputedAttributes)
+	t.Assertions = mergeAssertions(base.Assertions, t.Assertions)
+
+	return t
+}
+
+func mergeSections(baseSections []section, sections []section) []section {
+	merged := append([]section{}, baseSections...)
+
+	for _, overridingSection := range sections {
+		replaced := false
+
+		for index := range merged {
+			if overridingSection.ID != "" && merged[index].ID == overridingSection.ID {
+				merged[index] = overridingSection
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			merged = append(merged, overridingSection)
+		}
+	}
+
+	return merged
+}
+
+func mergeComputedAttributes(baseAttributes []computedAttribute, attributes []computedAttribute) []computedAttribute {
+	merged := append([]computedAttribute{}, baseAttributes...)
+
+	for _, overridingAttribute := range attributes {
+		replaced := false
+
+		for index := range merged {
+			if merged[index].AttributeName == overridingAttribute.AttributeName {
+				merged[index] = overridingAttribute
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			merged = append(merged, overridingAttribute)
+		}
+	}
+
+	return merged
+}
+
+func mergeAssertions(baseAssertions []assertion, assertions []assertion) []assertion {
+	merged := append([]assertion{}, baseAssertions...)
+
+	for _, overridingAssertion := range assertions {
+		replaced := false
+
+		for index := range merged {
+			if merged[index].Name == overridingAssertion.Name {
+				merged[index] = overridingAssertion
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			merged = append(merged, overridingAssertion)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,178 @@
+package osmosis
+
+import (
+	"strings"
+	"testing"
+)
+
+var inheritanceConfig = `{
+	"templates": [
+		{
+			"templateName": "Uber US",
+			"extends": "Uber base",
+			"matchers": {
+				"matcherType": "oneWordMatcher",
+				"words": "Uber USA"
+			},
+			"sections" : [
+				{
+					"id": "fare",
+					"contentSelector": {
+						"selectorType": "textBlockSelector"
+					},
+					"contentExtractors": [
+						{
+							"extractorType": "regexExtractor",
+							"regex": "Fare:\s+\$([\d.]+)",
+							"attributeName": "fare",
+							"defaultValue":"NA",
+							"groupNumber":1
+						}
+					]
+				}
+			]
+		},
+		{
+			"templateName": "Uber base",
+			"abstract": true,
+			"sections" : [
+				{
+					"id": "fare",
+					"contentSelector": {
+						"selectorType": "textBlockSelector"
+					},
+					"contentExtractors": [
+						{
+							"extractorType": "regexExtractor",
+							"regex": "Fare:\s+₹\s*([\d.]+)",
+							"attributeName": "fare",
+							"defaultValue":"NA",
+							"groupNumber":1
+						}
+					]
+				},
+				{
+					"id": "trip",
+					"contentSelector": {
+						"selectorType": "textBlockSelector"
+					},
+					"contentExtractors": [
+						{
+							"extractorType": "regexExtractor",
+							"regex": "Trip ID\s+(\w+)",
+							"attributeName": "tripId",
+							"defaultValue":"NA",
+							"groupNumber":1
+						}
+					]
+				}
+			]
+		},
+		{
+			"templateName": "Uber India",
+			"extends": "Uber base",
+			"matchers": {
+				"matcherType": "oneWordMatcher",
+				"words": "Uber India"
+			},
+			"sections" : [
+				{
+					"contentSelector": {
+						"selectorType": "textBlockSelector"
+					},
+					"contentExtractors": [
+						{
+							"extractorType": "gstinExtractor",
+							"attributeName": "gstin"
+						}
+					]
+				}
+			]
+		}
+	]
+}`
+
+func TestThatTemplateInheritsAndReplacesSectionsById(t *testing.T) {
+	templates, err := LoadConfig(strings.NewReader(inheritanceConfig))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader("Uber USA\nTrip ID AB12\nFare: $24.50"))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be raised but was %s", err.Error())
+	}
+
+	values := attributeValues(keyValuePairs)
+
+	if len(keyValuePairs) != 2 || values["fare"] != "24.50" || values["tripId"] != "AB12" {
+		t.Errorf("Expected fare section to be replaced and trip section to be inherited but got %v", values)
+	}
+}
+
+func TestThatTemplateAppendsSectionsWithoutMatchingId(t *testing.T) {
+	templates, _ := LoadConfig(strings.NewReader(inheritanceConfig))
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader("Uber India\nTrip ID XY34\nFare: ₹ 240.00\nGSTIN 29AAACU5552C1ZQ"))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be raised but was %s", err.Error())
+	}
+
+	values := attributeValues(keyValuePairs)
+
+	if len(keyValuePairs) != 3 || values["fare"] != "240.00" || values["tripId"] != "XY34" {
+		t.Errorf("Expected inherited sections followed by the appended one but got %v", values)
+	}
+
+	if keyValuePairs[2].AttributeName != "gstin" {
+		t.Errorf("Expected appended section to run after inherited ones but got %s", keyValuePairs[2].AttributeName)
+	}
+}
+
+func TestThatAbstractTemplateNeverMatchesOnItsOwn(t *testing.T) {
+	templates, _ := LoadConfig(strings.NewReader(inheritanceConfig))
+
+	keyValuePairs, _ := templates.ParseText(strings.NewReader("Trip ID XY34\nFare: ₹ 240.00"))
+
+	if len(keyValuePairs) != 0 {
+		t.Errorf("Did not expect abstract template to extract anything but got %v", keyValuePairs)
+	}
+}
+
+func TestThatCyclicAndUnknownBaseTemplatesReturnError(t *testing.T) {
+	cyclicConfig := `{
+		"templates": [
+			{"templateName": "A", "extends": "B", "matchers": {"matcherType": "oneWordMatcher", "words": "A"}},
+			{"templateName": "B", "extends": "A", "matchers": {"matcherType": "oneWordMatcher", "words": "B"}}
+		]
+	}`
+	unknownConfig := `{
+		"templates": [
+			{"templateName": "A", "extends": "Missing", "matchers": {"matcherType": "oneWordMatcher", "words": "A"}}
+		]
+	}`
+
+	if _, err := LoadConfig(strings.NewReader(cyclicConfig)); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("Expected cycle of extends to return an error but got %v", err)
+	}
+
+	if _, err := LoadConfig(strings.NewReader(unknownConfig)); err == nil || !strings.Contains(err.Error(), "Missing") {
+		t.Errorf("Expected unknown base template to return an error but got %v", err)
+	}
+}
+
+func TestThatTemplateWithoutMatcherMustBeAbstractOrExtendAConcreteTemplate(t *testing.T) {
+	config := `{
+		"templates": [
+			{"templateName": "Base", "abstract": true},
+			{"templateName": "Child", "extends": "Base"}
+		]
+	}`
+
+	if _, err := LoadConfig(strings.NewReader(config)); err == nil {
+		t.Errorf("Expected an error for a concrete template without matchers")
+	}
+}
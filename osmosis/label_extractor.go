@@ -0,0 +1,209 @@
+package osmosis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/buger/jsonparser"
+)
+
+const (
+	sameLinePosition = "sameLine"
+	nextLinePosition = "nextLine"
+	anyLinePosition  = "any"
+)
+
+type labelExtractor struct {
+	AttributeName string
+	DefaultValue  string
+	Labels        []string
+	Position      string
+	Terminators   []string
+	MaxDistance   int64
+}
+
+var wordRegex = regexp.MustCompile(`\S+`)
+
+//valueSeparatorRegex matches the separators between a label and its value, e.g. the colon in "Name: Jacob" or the dots in
+//"Total ..... 140.0". A single dash or dot is only a separator when it is not followed by the value, so "Discount: -20.00" keeps its sign.
+var valueSeparatorRegex = regexp.MustCompile(`^(?:\s*(?:[:=|–—]|\.{2,}|-{2,}|[-.](?:\s|$)))*\s*`)
+
+func getLabelExtractor(value []byte) labelExtractor {
+	attributeName, _ := jsonparser.GetString(value, "attributeName")
+	defaultValue, _ := jsonparser.GetString(value, "defaultValue")
+	position, err := jsonparser.GetString(value, "position")
+
+	if err != nil {
+		position = anyLinePosition
+	}
+
+	maxDistance, err := jsonparser.GetInt(value, "maxDistance")
+
+	if err != nil {
+		maxDistance = 0
+		if fuzzy, _ := jsonparser.GetBoolean(value, "fuzzy"); fuzzy {
+			maxDistance = -1
+		}
+	}
+
+	labels := getStringList(value, "labels")
+	if label, err := jsonparser.GetString(value, "label"); err == nil {
+		labels = append(labels, label)
+	}
+
+	return labelExtractor{
+		AttributeName: attributeName,
+		DefaultValue:  defaultValue,
+		Labels:        labels,
+		Position:      position,
+		Terminators:   getStringList(value, "terminators"),
+		MaxDistance:   maxDistance,
+	}
+}
+
+func (le labelExtractor) asContentExtractor() (contentExtractor, error) {
+	if len(le.Labels) == 0 {
+		return nil, fmt.Errorf("ERROR: Label extractor for %s requires at least one label", le.AttributeName)
+	}
+
+	if le.Position != sameLinePosition && le.Position != nextLinePosition && le.Position != anyLinePosition {
+		return nil, fmt.Errorf("ERROR: Label extractor position should be one of %s, %s or %s but was %s", sameLinePosition, nextLinePosition, anyLinePosition, le.Position)
+	}
+
+	return func(c content) []ExtractedContent {
+		extractedKeyVal := ExtractedContent{
+			AttributeName:  le.AttributeName,
+			AttributeValue: le.DefaultValue,
+		}
+
+		lines := strings.Split(c.OriginalText, "\n")
+
+		for index, line := range lines {
+			remainder, found := le.findLabel(line)
+
+			if !found {
+				continue
+			}
+
+			value := valueSeparatorRegex.ReplaceAllString(remainder, "")
+
+			if le.Position == nextLinePosition || (le.Position == anyLinePosition && strings.TrimSpace(value) == "") {
+				value = nextNonEmptyLine(lines[index+1:])
+			}
+
+			if value = le.terminate(value); value != "" {
+				extractedKeyVal.AttributeValue = value
+				return []ExtractedContent{extractedKeyVal}
+			}
+		}
+
+		return []ExtractedContent{extractedKeyVal}
+	}, nil
+}
+
+//findLabel looks for any of the labels in the line, comparing word by word, and returns the text following it
+func (le labelExtractor) findLabel(line string) (string, bool) {
+	wordBounds := wordRegex.FindAllStringIndex(line, -1)
+
+	for _, label := range le.Labels {
+		labelWords := strings.Fields(normalizeLabel(label))
+		allowedDistance := int(le.MaxDistance)
+
+		if allowedDistance < 0 {
+			allowedDistance = len([]rune(strings.Join(labelWords, " "))) / 5
+		}
+
+		for start := 0; start+len(labelWords) <= len(wordBounds) && len(labelWords) > 0; start++ {
+			end := wordBounds[start+len(labelWords)-1][1]
+			candidate := normalizeLabel(line[wordBounds[start][0]:end])
+
+			if editDistance(candidate, strings.Join(labelWords, " ")) <= allowedDistance {
+				return line[end:], true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func (le labelExtractor) terminate(value string) string {
+	for _, terminator := range le.Terminators {
+		if index := strings.Index(value, terminator); index >= 0 {
+			value = value[:index]
+		}
+	}
+
+	return strings.TrimSpace(value)
+}
+
+func nextNonEmptyLine(lines []string) string {
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return line
+		}
+	}
+
+	return ""
+}
+
+//normalizeLabel lower cases the text, drops punctuation and collapses whitespace so that "Invoice No.:" matches "invoice no"
+func normalizeLabel(text string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+		return -1
+	}, text)
+
+	return strings.Join(strings.Fields(cleaned), " ")
+}
+
+//editDistance is the levenshtein distance between two strings, counted in runes
+func editDistance(first, second string) int {
+	firstRunes := []rune(first)
+	secondRunes := []rune(second)
+	previous := make([]int, len(secondRunes)+1)
+	current := make([]int, len(secondRunes)+1)
+
+	for index := range previous {
+		previous[index] = index
+	}
+
+	for i := 1; i <= len(firstRunes); i++ {
+		current[0] = i
+		for j := 1; j <= len(secondRunes); j++ {
+			cost := 1
+			if firstRunes[i-1] == secondRunes[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(secondRunes)]
+}
+
+func minInt(first, second int) int {
+	if first < second {
+		return first
+	}
+	return second
+}
+
+func getStringList(value []byte, key string) []string {
+	values := make([]string, 0)
+
+	jsonparser.ArrayEach(value, func(item []byte, dataType jsonparser.ValueType, offset int, err error) {
+		if parsed, err := jsonparser.ParseString(item); err == nil {
+			values = append(values, parsed)
+		}
+	}, key)
+
+	return values
+}
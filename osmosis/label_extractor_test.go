@@ -0,0 +1,90 @@
+package osmosis
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestThatLabelExtractorFindsValueOnTheNextLine(t *testing.T) {
+	receipt, _ := ioutil.ReadFile("../examples/textfiles/freshmenu_receipt.txt")
+	c := content{OriginalText: string(receipt)}
+	c.prepare()
+
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "labelExtractor", "attributeName": "name", "label": "Name", "defaultValue": "NA"}`))
+
+	if value := extractor(c)[0].AttributeValue; value != "Juhi Chawla" {
+		t.Errorf("Expected name on the line after the label to be Juhi Chawla but got %s", value)
+	}
+}
+
+func TestThatLabelExtractorFindsValueOnTheSameLineWithAliasLabels(t *testing.T) {
+	c := content{OriginalText: contentString}
+	c.prepare()
+
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "labelExtractor", "attributeName": "invoiceNumber", "labels": ["Invoice No", "Invoice ID"]}`))
+
+	if value := extractor(c)[0].AttributeValue; value != "1IE88NHTQ55547" {
+		t.Errorf("Expected invoice number 1IE88NHTQ55547 but got %s", value)
+	}
+}
+
+func TestThatLabelExtractorMatchesMisspelledLabelsWhenFuzzy(t *testing.T) {
+	c := content{OriginalText: "Custmer Nane: Jacob | Phone: 9876543210"}
+	c.prepare()
+
+	fuzzyExtractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "labelExtractor", "attributeName": "name", "label": "Customer Name", "fuzzy": true, "terminators": ["|"]}`))
+	exactExtractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "labelExtractor", "attributeName": "name", "label": "Customer Name", "defaultValue": "NA"}`))
+
+	if value := fuzzyExtractor(c)[0].AttributeValue; value != "Jacob" {
+		t.Errorf("Expected fuzzy label match to return Jacob but got %s", value)
+	}
+
+	if value := exactExtractor(c)[0].AttributeValue; value != "NA" {
+		t.Errorf("Expected exact label match to return default value but got %s", value)
+	}
+}
+
+func TestThatLabelExtractorRespectsConfiguredPosition(t *testing.T) {
+	c := content{OriginalText: "Total ..... 140.0\n147.0"}
+	c.prepare()
+
+	sameLine, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "labelExtractor", "attributeName": "total", "label": "Total", "position": "sameLine"}`))
+	nextLine, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "labelExtractor", "attributeName": "total", "label": "Total", "position": "nextLine"}`))
+
+	if value := sameLine(c)[0].AttributeValue; value != "140.0" {
+		t.Errorf("Expected value on same line to be 140.0 but got %s", value)
+	}
+
+	if value := nextLine(c)[0].AttributeValue; value != "147.0" {
+		t.Errorf("Expected value on next line to be 147.0 but got %s", value)
+	}
+}
+
+func TestThatEditDistanceCountsRuneEdits(t *testing.T) {
+	if distance := editDistance("kitten", "sitting"); distance != 3 {
+		t.Errorf("Expected edit distance of 3 but got %d", distance)
+	}
+}
+
+func TestThatLabelExtractorKeepsTheSignAndDotOfAmounts(t *testing.T) {
+	expectations := map[string]string{
+		"Discount: -20.00":     "-20.00",
+		"Total: .50":           ".50",
+		"Total ..... 140.0":    "140.0",
+		"Total - 140.0":        "140.0",
+		"Total --- -5.00":      "-5.00",
+		"Total :- 99.00":       "99.00",
+		"Total | Rs. 1,200.00": "Rs. 1,200.00",
+	}
+
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "labelExtractor", "attributeName": "amount", "labels": ["Total", "Discount"], "position": "sameLine"}`))
+
+	for text, expected := range expectations {
+		c := content{OriginalText: text}
+		c.prepare()
+
+		if value := extractor(c)[0].AttributeValue; value != expected {
+			t.Errorf("Expected %s for [%s] but got %s", expected, text, value)
+		}
+	}
+}
@@ -277,7 +277,7 @@ func TestThatRegexMatcherWillReturnMatchedSelection(t *testing.T) {
 
 	selector, _ := classifyAndBuildSelector([]byte(positiveSelector))
 
-	selectedContent := selector(c)
+	selectedContent := selector(c)[0]
 
 	if strings.Compare(selectedContent.OriginalText, expectedContent) != 0 {
 		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedContent)
@@ -296,7 +296,7 @@ func TestThatRegexMatcherWillReturnEmptyContentWhenNothingMatches(t *testing.T)
 
 	selector, _ := classifyAndBuildSelector([]byte(positiveSelector))
 
-	selectedContent := selector(c)
+	selectedContent := selector(c)[0]
 
 	if strings.Compare(selectedContent.OriginalText, expectedContent) != 0 {
 		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedContent)
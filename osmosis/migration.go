@@ -0,0 +1,170 @@
+package osmosis
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/buger/jsonparser"
+)
+
+//CurrentConfigVersion is the version of the JSON DSL understood by this release. A config without a version key is version 1.
+const CurrentConfigVersion = 1
+
+//MigrationReport lists what was changed to upgrade a config from FromVersion to ToVersion
+type MigrationReport struct {
+	FromVersion int
+	ToVersion   int
+	Changes     []string
+}
+
+//configMigration upgrades a single object of a config from FromVersion to FromVersion + 1. It returns the upgraded object and
+//a description of each change it made.
+type configMigration struct {
+	FromVersion int
+	Migrate     func(object []byte) ([]byte, []string, error)
+}
+
+//configMigrations are applied in order. A change to the DSL that breaks existing configs adds a migration from the current version and
+//increments CurrentConfigVersion.
+var configMigrations = []configMigration{}
+
+//MigrateConfig upgrades a config in any of the supported formats to the current version of the JSON DSL. The upgraded config is
+//returned as canonical JSON along with a report of the changes. A config that is already current is only reformatted.
+func MigrateConfig(reader io.Reader) ([]byte, MigrationReport, error) {
+	config, err := ioutil.ReadAll(reader)
+
+	if err != nil {
+		return nil, MigrationReport{}, err
+	}
+
+	if config, err = configToJSON("", config); err != nil {
+		return nil, MigrationReport{}, err
+	}
+
+	migrated, report, err := migrateConfig(config)
+
+	if err != nil {
+		return nil, report, err
+	}
+
+	canonical, err := canonicalJSON(migrated)
+	return canonical, report, err
+}
+
+//configVersion reads the version key of the config. Configs written before the key existed are version 1.
+func configVersion(config []byte) (int, error) {
+	version, dataType, _, err := jsonparser.Get(config, "version")
+
+	if err == jsonparser.KeyPathNotFoundError {
+		return 1, nil
+	}
+
+	if err != nil || dataType != jsonparser.Number {
+		return 0, fmt.Errorf("ERROR: Config version should be a number but was %s", string(version))
+	}
+
+	versionNumber, err := strconv.Atoi(string(version))
+
+	if err != nil || versionNumber < 1 {
+		return 0, fmt.Errorf("ERROR: Config version %s is not valid", string(version))
+	}
+
+	if versionNumber > CurrentConfigVersion {
+		return 0, fmt.Errorf("ERROR: Config version %d is newer than version %d supported by this release", versionNumber, CurrentConfigVersion)
+	}
+
+	return versionNumber, nil
+}
+
+//migrateConfig applies the migrations for every version between the version of the config and the current version, so that the
+//rest of the loader only has to understand the current DSL
+func migrateConfig(config []byte) ([]byte, MigrationReport, error) {
+	version, err := configVersion(config)
+	report := MigrationReport{FromVersion: version, ToVersion: CurrentConfigVersion, Changes: make([]string, 0)}
+
+	if err != nil {
+		return nil, report, err
+	}
+
+	for _, migration := range configMigrations {
+		if migration.FromVersion < version {
+			continue
+		}
+
+		migrated, changes, err := migrateObjects(config, jsonparser.Object, "", migration.Migrate)
+
+		if err != nil {
+			return nil, report, fmt.Errorf("ERROR: Could not migrate config from version %d. Error is %s", migration.FromVersion, err.Error())
+		}
+
+		config = migrated
+		report.Changes = append(report.Changes, changes...)
+	}
+
+	if version == CurrentConfigVersion {
+		return config, report, nil
+	}
+
+	config, err = jsonparser.Set(config, []byte(strconv.Itoa(CurrentConfigVersion)), "version")
+	return config, report, err
+}
+
+//migrateObjects applies the migration to every object in the value, innermost objects first. Changes are prefixed with the path
+//of the object they were made to.
+func migrateObjects(value []byte, dataType jsonparser.ValueType, path string, migrate func(object []byte) ([]byte, []string, error)) ([]byte, []string, error) {
+	changes := make([]string, 0)
+	var migrateError error
+
+	switch dataType {
+	case jsonparser.Array:
+		items := make([][]byte, 0)
+		index := 0
+
+		jsonparser.ArrayEach(value, func(item []byte, itemType jsonparser.ValueType, offset int, err error) {
+			if migrateError == nil {
+				var itemChanges []string
+				item, itemChanges, migrateError = migrateObjects(item, itemType, fmt.Sprintf("%s[%d]", path, index), migrate)
+				items = append(items, item)
+				changes = append(changes, itemChanges...)
+			}
+			index++
+		})
+
+		return append(append([]byte("["), bytes.Join(items, []byte(","))...), ']'), changes, migrateError
+	case jsonparser.Object:
+		entries := make([][]byte, 0)
+
+		migrateError = jsonparser.ObjectEach(value, func(key []byte, entry []byte, entryType jsonparser.ValueType, offset int) error {
+			entry, entryChanges, err := migrateObjects(entry, entryType, joinConfigPath(path, string(key)), migrate)
+			entries = append(entries, append([]byte(`"`+string(key)+`":`), entry...))
+			changes = append(changes, entryChanges...)
+			return err
+		})
+
+		if migrateError != nil {
+			return nil, nil, migrateError
+		}
+
+		migrated, objectChanges, err := migrate(append(append([]byte("{"), bytes.Join(entries, []byte(","))...), '}'))
+
+		for _, change := range objectChanges {
+			changes = append(changes, joinConfigPath(path, change))
+		}
+
+		return migrated, changes, err
+	case jsonparser.String:
+		return []byte(`"` + string(value) + `"`), changes, nil
+	}
+
+	return value, changes, nil
+}
+
+func joinConfigPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,106 @@
+package osmosis
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/buger/jsonparser"
+)
+
+var versionOneConfig = `{
+	"templates": [
+		{
+			"templateName": "Ola",
+			"matchers": {
+				"matcherType": "conditionalMatcher",
+				"condition": "and",
+				"expressions": [
+					{"matcherType": "regexMatcher", "regexExpression": "ANI\\s+Technologies"},
+					{"matcherType": "oneWordMatcher", "words": "Ola"}
+				]
+			}
+		}
+	],
+	"definitions": {"invoiceMatcher": {"matcherType": "oneWordMatcher", "words": "Invoice"}}
+}`
+
+func TestThatConfigWithoutVersionNeedsNoChanges(t *testing.T) {
+	migrated, report, err := MigrateConfig(strings.NewReader(versionOneConfig))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	if report.FromVersion != 1 || report.ToVersion != CurrentConfigVersion || len(report.Changes) != 0 {
+		t.Errorf("Expected a config without version to be current but got %v", report)
+	}
+
+	if !strings.Contains(string(migrated), `"regexExpression": "ANI\\s+Technologies"`) {
+		t.Errorf("Expected the config to be kept as it was written but got %s", string(migrated))
+	}
+}
+
+func TestThatMigrationsAreAppliedToEveryObjectWithReport(t *testing.T) {
+	defer func(migrations []configMigration) { configMigrations = migrations }(configMigrations)
+
+	configMigrations = []configMigration{{FromVersion: 1, Migrate: func(object []byte) ([]byte, []string, error) {
+		words, err := jsonparser.GetString(object, "words")
+
+		if err != nil {
+			return object, nil, nil
+		}
+
+		object, err = jsonparser.Set(object, []byte(`"`+strings.ToUpper(words)+`"`), "words")
+		return object, []string{fmt.Sprintf("words: upper cased to %s", strings.ToUpper(words))}, err
+	}}}
+
+	migrated, report, err := MigrateConfig(strings.NewReader(versionOneConfig))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	if len(report.Changes) != 2 || !strings.HasPrefix(report.Changes[0], "templates[0].matchers.expressions[1].words") || !strings.HasPrefix(report.Changes[1], "definitions.invoiceMatcher.words") {
+		t.Errorf("Expected changes to name the path of the migrated objects but got %v", report.Changes)
+	}
+
+	for _, expected := range []string{`"words": "OLA"`, `"words": "INVOICE"`, `"regexExpression": "ANI\\s+Technologies"`} {
+		if !strings.Contains(string(migrated), expected) {
+			t.Errorf("Expected migrated config to contain %s but got %s", expected, string(migrated))
+		}
+	}
+}
+
+func TestThatVersionedConfigLoadsTheSameTemplates(t *testing.T) {
+	versionedConfig := strings.Replace(versionOneConfig, `"templates"`, `"version": 1, "templates"`, 1)
+
+	for _, config := range []string{versionOneConfig, versionedConfig} {
+		templates, err := LoadConfig(strings.NewReader(config))
+
+		if err != nil {
+			t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+		}
+
+		c := content{OriginalText: "ANI  Technologies for Ola"}
+		c.prepare()
+
+		if !templates["Ola"].Matcher(c) {
+			t.Errorf("Expected the regex matcher to match with and without a version")
+		}
+	}
+}
+
+func TestThatUnsupportedVersionsAreRejected(t *testing.T) {
+	invalidConfigs := map[string]string{
+		"newer version":       `{"version": 2, "templates": []}`,
+		"non numeric version": `{"version": "two", "templates": []}`,
+		"zero version":        `{"version": 0, "templates": []}`,
+	}
+
+	for description, config := range invalidConfigs {
+		if _, err := LoadConfig(strings.NewReader(config)); err == nil {
+			t.Errorf("Expected an error for %s", description)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package osmosis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/buger/jsonparser"
+)
+
+//pageFurniture removes headers and footers repeated on every page before a template matches and selects content.
+//Lines at the top or bottom of a page are removed when they repeat on every page, ignoring digits so that page numbers still match,
+//or when they match one of the configured patterns. When Keep is set, the removed lines stay selectable using a furnitureSelector.
+type pageFurniture struct {
+	PageDelimiter  string
+	MaxLines       int64
+	DetectRepeated bool
+	Patterns       []*regexp.Regexp
+	Keep           bool
+}
+
+type furnitureSelector struct {
+	Region string
+}
+
+const (
+	headerRegion = "header"
+	footerRegion = "footer"
+)
+
+var digitRegex = regexp.MustCompile(`\d+`)
+
+func buildPageFurniture(templateDef []byte) (*pageFurniture, error) {
+	furnitureDef, _, _, err := jsonparser.Get(templateDef, "pageFurniture")
+
+	if err != nil {
+		return nil, nil
+	}
+
+	pageDelimiter, err := jsonparser.GetString(furnitureDef, "pageDelimiter")
+
+	if err != nil || pageDelimiter == "" {
+		pageDelimiter = defaultPageDelimiter
+	}
+
+	maxLines, err := jsonparser.GetInt(furnitureDef, "maxLines")
+
+	if err != nil {
+		maxLines = 3
+	}
+
+	detectRepeated, err := jsonparser.GetBoolean(furnitureDef, "detectRepeated")
+
+	if err != nil {
+		detectRepeated = true
+	}
+
+	keep, _ := jsonparser.GetBoolean(furnitureDef, "keep")
+	patterns := make([]*regexp.Regexp, 0)
+	var patternError error
+
+	jsonparser.ArrayEach(furnitureDef, func(pattern []byte, dataType jsonparser.ValueType, offset int, err error) {
+		compiledRegex, err := regexp.Compile(string(pattern))
+
+		if err != nil {
+			patternError = fmt.Errorf("ERROR: Page furniture pattern %s did not compile. Error is %s", string(pattern), err.Error())
+			return
+		}
+
+		patterns = append(patterns, compiledRegex)
+	}, "patterns")
+
+	if patternError != nil {
+		return nil, patternError
+	}
+
+	return &pageFurniture{
+		PageDelimiter:  pageDelimiter,
+		MaxLines:       maxLines,
+		DetectRepeated: detectRepeated,
+		Patterns:       patterns,
+		Keep:           keep,
+	}, nil
+}
+
+func (pf *pageFurniture) strip(c content) content {
+	if pf == nil {
+		return c
+	}
+
+	pages := strings.Split(c.OriginalText, pf.PageDelimiter)
+	pageLines := make([][]string, len(pages))
+
+	for index, page := range pages {
+		pageLines[index] = strings.Split(page, "\n")
+	}
+
+	headerLines := make([]string, 0)
+	footerLines := make([]string, 0)
+
+	for index, lines := range pageLines {
+		if index == len(pageLines)-1 && len(pageLines) > 1 && strings.TrimSpace(pages[index]) == "" {
+			continue
+		}
+
+		headerEnd := pf.furnitureEnd(lines, pageLines)
+		footerStart := len(lines) - pf.furnitureEnd(reversed(lines), reversedPages(pageLines))
+
+		if footerStart < headerEnd {
+			footerStart = headerEnd
+		}
+
+		headerLines = append(headerLines, nonEmptyLines(lines[:headerEnd])...)
+		footerLines = append(footerLines, nonEmptyLines(lines[footerStart:])...)
+		pages[index] = strings.Join(lines[headerEnd:footerStart], "\n")
+	}
+
+	stripped := newSelectedContent(strings.Join(pages, pf.PageDelimiter))
+
+	if pf.Keep {
+		stripped.Header = strings.Join(headerLines, "\n")
+		stripped.Footer = strings.Join(footerLines, "\n")
+	}
+
+	return stripped
+}
+
+//furnitureEnd returns the index of the first line after the furniture at the top of a page. Footers are found by passing the lines
+//reversed. Blank lines between furniture lines are skipped over and at most MaxLines non blank lines are considered.
+func (pf *pageFurniture) furnitureEnd(lines []string, pageLines [][]string) int {
+	end := 0
+	position := 0
+
+	for index := 0; index < len(lines) && int64(position) < pf.MaxLines; index++ {
+		if strings.TrimSpace(lines[index]) == "" {
+			continue
+		}
+
+		if !pf.matchesPattern(lines[index]) && !(pf.DetectRepeated && repeatsOnEveryPage(lines[index], position, pageLines)) {
+			break
+		}
+
+		end = index + 1
+		position++
+	}
+
+	return end
+}
+
+func (pf *pageFurniture) matchesPattern(line string) bool {
+	for _, pattern := range pf.Patterns {
+		if pattern.MatchString(line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//repeatsOnEveryPage checks that every page has the same line, ignoring digits, at the same position among its non blank lines.
+//A document with a single page has no repeated lines.
+func repeatsOnEveryPage(line string, position int, pageLines [][]string) bool {
+	comparedPages := 0
+	normalizedLine := digitRegex.ReplaceAllString(strings.TrimSpace(line), "#")
+
+	for _, lines := range pageLines {
+		nonEmpty := nonEmptyLines(lines)
+
+		if len(nonEmpty) == 0 {
+			continue
+		}
+
+		if position >= len(nonEmpty) || digitRegex.ReplaceAllString(strings.TrimSpace(nonEmpty[position]), "#") != normalizedLine {
+			return false
+		}
+
+		comparedPages++
+	}
+
+	return comparedPages > 1
+}
+
+func nonEmptyLines(lines []string) []string {
+	nonEmpty := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			nonEmpty = append(nonEmpty, line)
+		}
+	}
+
+	return nonEmpty
+}
+
+func reversed(lines []string) []string {
+	reversedLines := make([]string, len(lines))
+
+	for index, line := range lines {
+		reversedLines[len(lines)-1-index] = line
+	}
+
+	return reversedLines
+}
+
+func reversedPages(pageLines [][]string) [][]string {
+	reversedPageLines := make([][]string, len(pageLines))
+
+	for index, lines := range pageLines {
+		reversedPageLines[index] = reversed(lines)
+	}
+
+	return reversedPageLines
+}
+
+func getFurnitureSelector(value []byte) furnitureSelector {
+	region, _ := jsonparser.GetString(value, "region")
+
+	return furnitureSelector{
+		Region: region,
+	}
+}
+
+func (fs furnitureSelector) asContentSelector() (contentSelector, error) {
+	if fs.Region != "" && !strings.EqualFold(fs.Region, headerRegion) && !strings.EqualFold(fs.Region, footerRegion) {
+		return nil, fmt.Errorf("ERROR: Furniture selector region should be %s or %s but was %s", headerRegion, footerRegion, fs.Region)
+	}
+
+	return func(c content) []content {
+		if strings.EqualFold(fs.Region, headerRegion) {
+			return []content{newSelectedContent(c.Header)}
+		}
+
+		if strings.EqualFold(fs.Region, footerRegion) {
+			return []content{newSelectedContent(c.Footer)}
+		}
+
+		return []content{newSelectedContent(strings.TrimSpace(c.Header + "\n" + c.Footer))}
+	}, nil
+}
@@ -0,0 +1,117 @@
+package osmosis
+
+import (
+	"strings"
+	"testing"
+)
+
+var furnishedStatement = "ACME Bank Statement\nAccount 00123\n\nDate  Description  Amount\n01/05  Opening  100.00\n\nPage 1 of 2\nacme.example.com\f" +
+	"ACME Bank Statement\nAccount 00123\nDate  Description  Amount\n02/05  Coffee  -4.50\nClosing balance 95.50\nPage 2 of 2\nacme.example.com\f"
+
+func TestThatRepeatedHeadersAndFootersAreStrippedFromEveryPage(t *testing.T) {
+	furniture, _ := buildPageFurniture([]byte(`{"pageFurniture": {"maxLines": 2}}`))
+	c := content{OriginalText: furnishedStatement}
+	c.prepare()
+
+	stripped := furniture.strip(c)
+
+	if strings.Contains(stripped.OriginalText, "ACME Bank") || strings.Contains(stripped.OriginalText, "Page 1 of 2") || strings.Contains(stripped.OriginalText, "acme.example.com") {
+		t.Errorf("Expected headers and footers to be stripped but got [%s]", stripped.OriginalText)
+	}
+
+	if !strings.Contains(stripped.OriginalText, "Date  Description  Amount\n02/05  Coffee  -4.50\nClosing balance 95.50") {
+		t.Errorf("Expected page body to be kept but got [%s]", stripped.OriginalText)
+	}
+
+	if stripped.Header != "" || stripped.Footer != "" {
+		t.Errorf("Did not expect stripped lines to be kept")
+	}
+}
+
+func TestThatConfiguredPatternsAreStrippedEvenFromASinglePage(t *testing.T) {
+	furniture, _ := buildPageFurniture([]byte(`{"pageFurniture": {"patterns": ["^Page \d+ of \d+$"], "detectRepeated": false, "keep": true}}`))
+	c := content{OriginalText: "Invoice 42\nTotal 100.00\nPage 1 of 1"}
+	c.prepare()
+
+	stripped := furniture.strip(c)
+
+	if stripped.OriginalText != "Invoice 42\nTotal 100.00" || stripped.Footer != "Page 1 of 1" {
+		t.Errorf("Expected page number footer to be stripped and kept but got [%s] and [%s]", stripped.OriginalText, stripped.Footer)
+	}
+}
+
+func TestThatTemplateWithoutPageFurnitureLeavesContentUntouched(t *testing.T) {
+	furniture, _ := buildPageFurniture([]byte(`{"templateName": "Plain"}`))
+	c := content{OriginalText: furnishedStatement}
+
+	if furniture.strip(c).OriginalText != furnishedStatement {
+		t.Errorf("Expected content to be untouched")
+	}
+}
+
+func TestThatKeptFurnitureCanBeSelectedAndExtracted(t *testing.T) {
+	templates, err := LoadConfig(strings.NewReader(`{
+		"templates": [
+			{
+				"templateName": "ACME statement",
+				"pageFurniture": {
+					"maxLines": 2,
+					"keep": true
+				},
+				"matchers": {
+					"matcherType": "oneWordMatcher",
+					"words": "Closing balance"
+				},
+				"sections" : [
+					{
+						"contentSelector": {
+							"selectorType": "furnitureSelector",
+							"region": "header"
+						},
+						"contentExtractors": [
+							{
+								"extractorType": "regexExtractor",
+								"regex": "Account (\d+)",
+								"attributeName": "accountNumber",
+								"defaultValue":"NA",
+								"groupNumber":1
+							}
+						]
+					},
+					{
+						"contentSelector": {
+							"selectorType": "regexSelector",
+							"regex": "(?s)Closing balance.*",
+							"groupNumber": 0
+						},
+						"contentExtractors": [
+							{
+								"extractorType": "regexExtractor",
+								"regex": "(\d+\.\d+)\s*$",
+								"attributeName": "closingBalance",
+								"defaultValue":"NA",
+								"groupNumber":1
+							}
+						]
+					}
+				]
+			}
+		]
+	}`))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader(furnishedStatement))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be raised but was %s", err.Error())
+	}
+
+	values := attributeValues(keyValuePairs)
+
+	if values["accountNumber"] != "00123" || values["closingBalance"] != "95.50" {
+		t.Errorf("Expected account number from header and closing balance without footer but got %v", values)
+	}
+}
@@ -0,0 +1,135 @@
+package osmosis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/buger/jsonparser"
+)
+
+const defaultPageDelimiter = "\f"
+
+type pageSelector struct {
+	PageDelimiter string
+	FromPage      int64
+	ToPage        int64
+}
+
+func getPageSelector(value []byte) pageSelector {
+	pageDelimiter, err := jsonparser.GetString(value, "pageDelimiter")
+
+	if err != nil || pageDelimiter == "" {
+		pageDelimiter = defaultPageDelimiter
+	}
+
+	fromPage, _ := jsonparser.GetInt(value, "fromPage")
+	toPage, _ := jsonparser.GetInt(value, "toPage")
+
+	if page, err := jsonparser.GetInt(value, "page"); err == nil {
+		fromPage, toPage = page, page
+	} else if page, err := jsonparser.GetString(value, "page"); err == nil {
+		if strings.EqualFold(page, "first") {
+			fromPage, toPage = 1, 1
+		} else if strings.EqualFold(page, "last") {
+			fromPage, toPage = -1, -1
+		}
+	}
+
+	return pageSelector{
+		PageDelimiter: pageDelimiter,
+		FromPage:      fromPage,
+		ToPage:        toPage,
+	}
+}
+
+func (ps pageSelector) asContentSelector() (contentSelector, error) {
+	if ps.FromPage > 0 && ps.ToPage > 0 && ps.FromPage > ps.ToPage {
+		return nil, fmt.Errorf("ERROR: Page selector fromPage %d is after toPage %d", ps.FromPage, ps.ToPage)
+	}
+
+	return func(c content) []content {
+		pages := splitPages(c.OriginalText, ps.PageDelimiter)
+		fromIndex, toIndex := 0, len(pages)-1
+
+		if ps.FromPage != 0 {
+			fromIndex = pageIndex(ps.FromPage, len(pages))
+		}
+
+		if ps.ToPage != 0 {
+			toIndex = minInt(pageIndex(ps.ToPage, len(pages)), len(pages)-1)
+		}
+
+		blocks := make([]content, 0)
+
+		for index := maxInt(fromIndex, 0); index <= toIndex; index++ {
+			page := newSelectedContent(pages[index])
+			page.Page = maxInt(c.Page, 1) + index
+			blocks = append(blocks, page)
+		}
+
+		if len(blocks) == 0 {
+			return []content{{OriginalText: ""}}
+		}
+
+		return blocks
+	}, nil
+}
+
+//splitPages splits text on the page delimiter. The empty page after a trailing delimiter, which PDF converters usually add, is dropped.
+func splitPages(text string, pageDelimiter string) []string {
+	pages := strings.Split(text, pageDelimiter)
+
+	if len(pages) > 1 && strings.TrimSpace(pages[len(pages)-1]) == "" {
+		pages = pages[:len(pages)-1]
+	}
+
+	return pages
+}
+
+//pageIndex converts a page number, where negative numbers count from the last page, to an index into the pages
+func pageIndex(page int64, pageCount int) int {
+	if page < 0 {
+		return pageCount + int(page)
+	}
+
+	return int(page) - 1
+}
+
+//pageBreaks returns the offsets of the page delimiters in text
+func pageBreaks(text string, pageDelimiter string) []int {
+	offsets := make([]int, 0)
+
+	for start := 0; ; {
+		offset := strings.Index(text[start:], pageDelimiter)
+		if offset < 0 {
+			break
+		}
+		offsets = append(offsets, start+offset)
+		start += offset + len(pageDelimiter)
+	}
+
+	return offsets
+}
+
+//pageAt returns the page number of the text at offset, counting the page breaks in front of it from the page the content starts on
+func (c content) pageAt(offset int) int {
+	page := maxInt(c.Page, 1)
+
+	for _, pageBreak := range c.PageBreaks {
+		if pageBreak < offset {
+			page++
+		}
+	}
+
+	return page
+}
+
+//locate sets the page a block selected from the content starts on from the offset it was selected at, unless its selector already
+//did. Selectors that rebuild the text, like the column selector, leave the block on the page the content starts on.
+func (c content) locate(block content) content {
+	if block.Page == 0 {
+		block.Page = c.pageAt(block.Offset)
+	}
+
+	return block
+}
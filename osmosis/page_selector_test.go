@@ -0,0 +1,279 @@
+package osmosis
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var threePageStatement = "Uber Trip Statement\nTrip 1\nFare: 100.0\nPage 1 of 3\f" +
+	"Trip 2\nFare: 150.0\nPage 2 of 3\f" +
+	"Trip 3\nFare: 90.5\nTotal: 340.5\nPage 3 of 3\f"
+
+func TestThatPageSelectorSelectsEveryPageAsABlock(t *testing.T) {
+	c := content{OriginalText: threePageStatement}
+	c.prepare()
+
+	selector, _ := classifyAndBuildSelector([]byte(`{"selectorType": "pageSelector"}`))
+	pages := selector(c)
+
+	if len(pages) != 3 {
+		t.Fatalf("Expected 3 pages ignoring the trailing page break but got %d", len(pages))
+	}
+
+	if pages[1].OriginalText != "Trip 2\nFare: 150.0\nPage 2 of 3" || pages[1].Page != 2 {
+		t.Errorf("Expected second page to be selected with its page number but got %v", pages[1])
+	}
+}
+
+func TestThatPageSelectorSelectsFirstAndLastPage(t *testing.T) {
+	c := content{OriginalText: threePageStatement}
+	c.prepare()
+
+	firstPageSelector, _ := classifyAndBuildSelector([]byte(`{"selectorType": "pageSelector", "page": "first"}`))
+	lastPageSelector, _ := classifyAndBuildSelector([]byte(`{"selectorType": "pageSelector", "page": "last"}`))
+	firstPage := firstPageSelector(c)
+	lastPage := lastPageSelector(c)
+
+	if len(firstPage) != 1 || firstPage[0].Page != 1 || !strings.HasPrefix(firstPage[0].OriginalText, "Uber Trip Statement") {
+		t.Errorf("Expected first page to be selected but got %v", firstPage)
+	}
+
+	if len(lastPage) != 1 || lastPage[0].Page != 3 || !strings.Contains(lastPage[0].OriginalText, "Total: 340.5") {
+		t.Errorf("Expected last page to be selected but got %v", lastPage)
+	}
+}
+
+func TestThatPageSelectorSelectsARangeOfPages(t *testing.T) {
+	c := content{OriginalText: threePageStatement}
+	c.prepare()
+
+	selector, _ := classifyAndBuildSelector([]byte(`{"selectorType": "pageSelector", "fromPage": 2, "toPage": -1}`))
+	pages := selector(c)
+
+	if len(pages) != 2 || pages[0].Page != 2 || pages[1].Page != 3 {
+		t.Errorf("Expected pages 2 and 3 to be selected but got %v", pages)
+	}
+}
+
+func TestThatPageSelectorSplitsOnConfiguredDelimiter(t *testing.T) {
+	selector, _ := classifyAndBuildSelector([]byte(`{"selectorType": "pageSelector", "pageDelimiter": "=== PAGE BREAK ===", "page": 2}`))
+	c := content{OriginalText: "Invoice 1\n=== PAGE BREAK ===\nTerms and conditions"}
+	c.prepare()
+
+	pages := selector(c)
+
+	if len(pages) != 1 || pages[0].OriginalText != "\nTerms and conditions" {
+		t.Errorf("Expected second page to be selected but got %v", pages)
+	}
+}
+
+func TestThatPageNumberIsRecordedForValuesExtractedFromPages(t *testing.T) {
+	templates, err := LoadConfig(strings.NewReader(`{
+		"templates": [
+			{
+				"templateName": "Uber statement",
+				"matchers": {
+					"matcherType": "oneWordMatcher",
+					"words": "Uber Trip Statement"
+				},
+				"sections" : [
+					{
+						"contentSelector": {
+							"selectorType": "pageSelector",
+							"contentSelector": {
+								"selectorType": "anchorSelector",
+								"anchorLine": -1
+							}
+						},
+						"contentExtractors": [
+							{
+								"extractorType": "regexExtractor",
+								"regex": "Page (\d+) of",
+								"attributeName": "footerPage",
+								"defaultValue":"NA",
+								"groupNumber":1
+							}
+						]
+					}
+				]
+			}
+		]
+	}`))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader(threePageStatement))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be raised but was %s", err.Error())
+	}
+
+	if len(keyValuePairs) != 3 {
+		t.Fatalf("Expected a value from every page but got %d", len(keyValuePairs))
+	}
+
+	for index, keyValue := range keyValuePairs {
+		if keyValue.Page != index+1 || keyValue.AttributeValue != strconv.Itoa(index+1) {
+			t.Errorf("Expected footer of page %d to be extracted from page %d but got %s from page %d", index+1, index+1, keyValue.AttributeValue, keyValue.Page)
+		}
+	}
+}
+
+func TestThatPageNumberIsRecordedForValuesSelectedWithoutAPageSelector(t *testing.T) {
+	templates, err := LoadConfig(strings.NewReader(`{
+		"templates": [
+			{
+				"templateName": "Uber statement",
+				"matchers": {
+					"matcherType": "oneWordMatcher",
+					"words": "Uber Trip Statement"
+				},
+				"sections" : [
+					{
+						"contentSelector": {
+							"selectorType": "textBlockSelector",
+							"fromText": "Trip 1",
+							"toText": "Page 3 of 3"
+						},
+						"contentExtractors": [
+							{
+								"extractorType": "regexExtractor",
+								"regex": "Total: ([\d.]+)",
+								"attributeName": "total",
+								"defaultValue":"NA",
+								"groupNumber":1
+							},
+							{
+								"extractorType": "regexExtractor",
+								"regex": "Refund: ([\d.]+)",
+								"attributeName": "refund",
+								"defaultValue":"NA",
+								"groupNumber":1
+							}
+						]
+					},
+					{
+						"contentSelector": {
+							"selectorType": "textBlockSelector",
+							"fromText": "Trip 2",
+							"toText": "Page 2 of 3"
+						},
+						"contentExtractors": [
+							{
+								"extractorType": "autoKeyValueExtractor",
+								"attributePrefix": "trip2."
+							}
+						]
+					}
+				]
+			}
+		]
+	}`))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader(threePageStatement))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be raised but was %s", err.Error())
+	}
+
+	expectedPages := map[string]int{"total": 3, "refund": 1, "trip2.fare": 2}
+
+	for _, keyValue := range keyValuePairs {
+		if page, found := expectedPages[keyValue.AttributeName]; found && keyValue.Page != page {
+			t.Errorf("Expected %s to be extracted from page %d but was from page %d", keyValue.AttributeName, page, keyValue.Page)
+		}
+		delete(expectedPages, keyValue.AttributeName)
+	}
+
+	if len(expectedPages) != 0 {
+		t.Errorf("Expected attributes %v to be extracted", expectedPages)
+	}
+}
+
+func TestThatPageNumberIsRecordedForNestedAndRepeatedBlocks(t *testing.T) {
+	templates, err := LoadConfig(strings.NewReader(`{
+		"templates": [
+			{
+				"templateName": "Uber statement",
+				"matchers": {
+					"matcherType": "oneWordMatcher",
+					"words": "Uber Trip Statement"
+				},
+				"sections" : [
+					{
+						"contentSelector": {
+							"selectorType": "textBlockSelector",
+							"fromText": "Trip 2",
+							"contentSelector": {
+								"selectorType": "lineNumberSelector",
+								"fromLine": 2,
+								"toLine": 2
+							}
+						},
+						"contentExtractors": [
+							{
+								"extractorType": "regexExtractor",
+								"regex": "Fare: ([\d.]+)",
+								"attributeName": "secondFare",
+								"defaultValue":"NA",
+								"groupNumber":1
+							}
+						]
+					},
+					{
+						"blockAttributeName": "pages",
+						"contentSelector": {
+							"selectorType": "regexSelector",
+							"regex": "Page \d of 3",
+							"all": true
+						},
+						"contentExtractors": [
+							{
+								"extractorType": "regexExtractor",
+								"regex": "Page (\d)",
+								"attributeName": "footer",
+								"defaultValue":"NA",
+								"groupNumber":1
+							}
+						]
+					}
+				]
+			}
+		]
+	}`))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, err := templates.ParseText(strings.NewReader(strings.Replace(threePageStatement, "Page 3 of 3", "Page 1 of 3", 1)))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be raised but was %s", err.Error())
+	}
+
+	expected := []ExtractedContent{
+		{AttributeName: "secondFare", AttributeValue: "150.0", Block: 1, Page: 2},
+		{AttributeName: "pages.1.footer", AttributeValue: "1", Block: 1, Page: 1},
+		{AttributeName: "pages.2.footer", AttributeValue: "2", Block: 2, Page: 2},
+		{AttributeName: "pages.3.footer", AttributeValue: "1", Block: 3, Page: 3},
+		{AttributeName: "pages.count", AttributeValue: "3"},
+	}
+
+	if len(keyValuePairs) != len(expected) {
+		t.Fatalf("Expected %d key value pairs but got %v", len(expected), keyValuePairs)
+	}
+
+	for index, keyValue := range expected {
+		if keyValuePairs[index] != keyValue {
+			t.Errorf("Expected %v at position %d but got %v", keyValue, index, keyValuePairs[index])
+		}
+	}
+}
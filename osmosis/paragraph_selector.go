@@ -0,0 +1,133 @@
+package osmosis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/buger/jsonparser"
+)
+
+type paragraphSelector struct {
+	Separators []string
+	Index      int64
+	AnchorText string
+	AllBlocks  bool
+}
+
+//defaultParagraphSeparator matches divider rows like ----- or =====. Blank lines always separate paragraphs.
+const defaultParagraphSeparator = `^\s*(-{3,}|={3,}|_{3,}|\*{3,})\s*$`
+
+func getParagraphSelector(value []byte) paragraphSelector {
+	separators := make([]string, 0)
+	anchorText, _ := jsonparser.GetString(value, "anchorText")
+	index, _ := jsonparser.GetInt(value, "index")
+	allBlocks, _ := jsonparser.GetBoolean(value, "all")
+
+	jsonparser.ArrayEach(value, func(separator []byte, dataType jsonparser.ValueType, offset int, err error) {
+		separators = append(separators, string(separator))
+	}, "separators")
+
+	if len(separators) == 0 {
+		separators = append(separators, defaultParagraphSeparator)
+	}
+
+	return paragraphSelector{
+		Separators: separators,
+		Index:      index,
+		AnchorText: anchorText,
+		AllBlocks:  allBlocks || (index == 0 && anchorText == ""),
+	}
+}
+
+func (ps paragraphSelector) asContentSelector() (contentSelector, error) {
+	separatorRegexes := make([]*regexp.Regexp, 0, len(ps.Separators))
+
+	for _, separator := range ps.Separators {
+		compiledRegex, err := regexp.Compile(separator)
+
+		if err != nil {
+			return nil, fmt.Errorf("ERROR: Separator regex %s for paragraph selector did not compile. Error is %s", separator, err.Error())
+		}
+
+		separatorRegexes = append(separatorRegexes, compiledRegex)
+	}
+
+	return func(c content) []content {
+		paragraphs := splitParagraphs(c.OriginalText, separatorRegexes)
+
+		if ps.Index != 0 {
+			index := int(ps.Index) - 1
+			if ps.Index < 0 {
+				index = len(paragraphs) + int(ps.Index)
+			}
+			if index < 0 || index >= len(paragraphs) {
+				return []content{{OriginalText: ""}}
+			}
+			return []content{paragraphs[index]}
+		}
+
+		blocks := make([]content, 0)
+
+		for _, paragraph := range paragraphs {
+			if ps.AnchorText != "" && !strings.Contains(paragraph.OriginalText, ps.AnchorText) {
+				continue
+			}
+
+			blocks = append(blocks, paragraph)
+
+			if !ps.AllBlocks {
+				break
+			}
+		}
+
+		if len(blocks) == 0 && !ps.AllBlocks {
+			return []content{{OriginalText: ""}}
+		}
+
+		return blocks
+	}, nil
+}
+
+//splitParagraphs splits text into paragraphs on blank lines and lines matching any of the separators. Separator lines are dropped.
+func splitParagraphs(text string, separatorRegexes []*regexp.Regexp) []content {
+	paragraphs := make([]content, 0)
+	paragraphLines := make([]string, 0)
+	lines := strings.Split(text, "\n")
+	paragraphStart := 0
+
+	for index, line := range lines {
+		if !isParagraphSeparator(line, separatorRegexes) {
+			if len(paragraphLines) == 0 {
+				paragraphStart = index
+			}
+			paragraphLines = append(paragraphLines, line)
+			continue
+		}
+
+		if len(paragraphLines) > 0 {
+			paragraphs = append(paragraphs, newSelectedContentAt(strings.Join(paragraphLines, "\n"), lineOffset(lines, paragraphStart)))
+			paragraphLines = make([]string, 0)
+		}
+	}
+
+	if len(paragraphLines) > 0 {
+		paragraphs = append(paragraphs, newSelectedContentAt(strings.Join(paragraphLines, "\n"), lineOffset(lines, paragraphStart)))
+	}
+
+	return paragraphs
+}
+
+func isParagraphSeparator(line string, separatorRegexes []*regexp.Regexp) bool {
+	if strings.TrimSpace(line) == "" {
+		return true
+	}
+
+	for _, separatorRegex := range separatorRegexes {
+		if separatorRegex.MatchString(line) {
+			return true
+		}
+	}
+
+	return false
+}
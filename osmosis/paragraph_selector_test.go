@@ -0,0 +1,70 @@
+package osmosis
+
+import (
+	"testing"
+)
+
+var dividedReceipt = `CAFE COFFEE DAY
+MG Road, Bengaluru
+-----------------
+Bill No: 4411
+Table: 7
+
+Cappuccino    2    240.00
+Brownie       1    120.00
+=================
+Total              360.00
+***
+Thank you, visit again`
+
+func TestThatParagraphSelectorSelectsAllBlocksByDefault(t *testing.T) {
+	c := content{OriginalText: dividedReceipt}
+	c.prepare()
+
+	selector, _ := classifyAndBuildSelector([]byte(`{"selectorType": "paragraphSelector"}`))
+	paragraphs := selector(c)
+
+	if len(paragraphs) != 5 {
+		t.Fatalf("Expected 5 paragraphs but got %d", len(paragraphs))
+	}
+
+	if paragraphs[1].OriginalText != "Bill No: 4411\nTable: 7" || paragraphs[3].OriginalText != "Total              360.00" {
+		t.Errorf("Expected paragraphs to be split on blank lines and dividers but got %v", paragraphs)
+	}
+}
+
+func TestThatParagraphSelectorSelectsByIndexCountingNegativeIndexFromTheEnd(t *testing.T) {
+	c := content{OriginalText: dividedReceipt}
+	c.prepare()
+
+	selector, _ := classifyAndBuildSelector([]byte(`{"selectorType": "paragraphSelector", "index": -1}`))
+	paragraphs := selector(c)
+
+	if len(paragraphs) != 1 || paragraphs[0].OriginalText != "Thank you, visit again" {
+		t.Errorf("Expected the last paragraph to be selected but got %v", paragraphs)
+	}
+}
+
+func TestThatParagraphSelectorSelectsTheBlockContainingAnchorText(t *testing.T) {
+	c := content{OriginalText: dividedReceipt}
+	c.prepare()
+
+	selector, _ := classifyAndBuildSelector([]byte(`{"selectorType": "paragraphSelector", "anchorText": "Brownie"}`))
+	paragraphs := selector(c)
+
+	if len(paragraphs) != 1 || paragraphs[0].OriginalText != "Cappuccino    2    240.00\nBrownie       1    120.00" {
+		t.Errorf("Expected the items paragraph to be selected but got %v", paragraphs)
+	}
+}
+
+func TestThatParagraphSelectorUsesConfiguredSeparators(t *testing.T) {
+	c := content{OriginalText: dividedReceipt}
+	c.prepare()
+
+	selector, _ := classifyAndBuildSelector([]byte(`{"selectorType": "paragraphSelector", "separators": ["^Table:"], "index": 1}`))
+	paragraphs := selector(c)
+
+	if len(paragraphs) != 1 || paragraphs[0].OriginalText != "CAFE COFFEE DAY\nMG Road, Bengaluru\n-----------------\nBill No: 4411" {
+		t.Errorf("Expected only the configured separator and blank lines to split paragraphs but got %v", paragraphs)
+	}
+}
@@ -8,11 +8,20 @@ import (
 	"github.com/buger/jsonparser"
 )
 
-type contentSelector func(c content) content
+//contentSelector selects one or more blocks of content. Sections run their extractors on every selected block.
+type contentSelector func(c content) []content
 
 type textBlockSelector struct {
-	FromText string
-	ToText   string
+	FromText        string
+	ToText          string
+	FromRegex       string
+	ToRegex         string
+	IncludeFrom     bool
+	IncludeTo       bool
+	Occurrence      int64
+	CaseInsensitive bool
+	Required        bool
+	AllBlocks       bool
 }
 
 type lineNumberSelector struct {
@@ -20,9 +29,15 @@ type lineNumberSelector struct {
 	ToLine   int64
 }
 
+type columnSelector struct {
+	FromColumn int64
+	ToColumn   int64
+}
+
 type regexSelector struct {
 	RegexPattern string
 	GroupNumber  int64
+	AllMatches   bool
 }
 
 func classifyAndBuildSelector(value []byte) (contentSelector, error) {
@@ -40,7 +55,7 @@ func classifyAndBuildSelector(value []byte) (contentSelector, error) {
 	}()
 
 	if err != nil {
-		return nil, fmt.Errorf("ERROR: Could not find tag selectorType in config. Error is " + err.Error())
+		return nil, fmt.Errorf("ERROR: Could not find tag selectorType in config. Error is %s", err.Error())
 	}
 
 	if strings.EqualFold(selectorType, "textBlockSelector") {
@@ -49,6 +64,24 @@ func classifyAndBuildSelector(value []byte) (contentSelector, error) {
 		selector, err = getLineNumberSelector(value).asContentSelector()
 	} else if strings.EqualFold(selectorType, "regexSelector") {
 		selector, err = getRegexSelector(value).asContentSelector()
+	} else if strings.EqualFold(selectorType, "columnSelector") {
+		selector, err = getColumnSelector(value).asContentSelector()
+	} else if strings.EqualFold(selectorType, "anchorSelector") {
+		selector, err = getAnchorSelector(value).asContentSelector()
+	} else if strings.EqualFold(selectorType, "paragraphSelector") {
+		selector, err = getParagraphSelector(value).asContentSelector()
+	} else if strings.EqualFold(selectorType, "pageSelector") {
+		selector, err = getPageSelector(value).asContentSelector()
+	} else if strings.EqualFold(selectorType, "furnitureSelector") {
+		selector, err = getFurnitureSelector(value).asContentSelector()
+	} else if strings.EqualFold(selectorType, "tableSelector") {
+		selector, err = getTableSelector(value).asContentSelector()
+	} else {
+		return nil, fmt.Errorf("ERROR: Unknown selector type %s", selectorType)
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
 	contentSelectorValue, _, _, err := jsonparser.Get(value, "contentSelector")
@@ -69,20 +102,47 @@ func classifyAndBuildSelector(value []byte) (contentSelector, error) {
 func getRegexSelector(value []byte) regexSelector {
 	regex, _, _, _ := jsonparser.Get(value, "regex")
 	groupNumber, _ := jsonparser.GetInt(value, "groupNumber")
+	allMatches, _ := jsonparser.GetBoolean(value, "all")
 
 	return regexSelector{
 		RegexPattern: string(regex),
 		GroupNumber:  groupNumber,
+		AllMatches:   allMatches,
 	}
 }
 
 func getTextBlockSelector(value []byte) textBlockSelector {
 	fromText, _ := jsonparser.GetString(value, "fromText")
 	toText, _ := jsonparser.GetString(value, "toText")
+	fromRegex, _, _, _ := jsonparser.Get(value, "fromRegex")
+	toRegex, _, _, _ := jsonparser.Get(value, "toRegex")
+	includeTo, _ := jsonparser.GetBoolean(value, "includeTo")
+	caseInsensitive, _ := jsonparser.GetBoolean(value, "caseInsensitive")
+	required, _ := jsonparser.GetBoolean(value, "required")
+	allBlocks, _ := jsonparser.GetBoolean(value, "all")
+	includeFrom, err := jsonparser.GetBoolean(value, "includeFrom")
+
+	if err != nil {
+		includeFrom = true
+	}
+
+	occurrence, err := jsonparser.GetInt(value, "occurrence")
+
+	if err != nil {
+		occurrence = 1
+	}
 
 	return textBlockSelector{
-		FromText: fromText,
-		ToText:   toText,
+		FromText:        fromText,
+		ToText:          toText,
+		FromRegex:       string(fromRegex),
+		ToRegex:         string(toRegex),
+		IncludeFrom:     includeFrom,
+		IncludeTo:       includeTo,
+		Occurrence:      occurrence,
+		CaseInsensitive: caseInsensitive,
+		Required:        required,
+		AllBlocks:       allBlocks,
 	}
 }
 
@@ -105,6 +165,25 @@ func getLineNumberSelector(value []byte) lineNumberSelector {
 	}
 }
 
+func getColumnSelector(value []byte) columnSelector {
+	fromColumn, err := jsonparser.GetInt(value, "fromColumn")
+
+	if err != nil {
+		fromColumn = -1
+	}
+
+	toColumn, err := jsonparser.GetInt(value, "toColumn")
+
+	if err != nil {
+		toColumn = -1
+	}
+
+	return columnSelector{
+		FromColumn: fromColumn,
+		ToColumn:   toColumn,
+	}
+}
+
 func (rs regexSelector) asContentSelector() (contentSelector, error) {
 	compiledRegex, err := regexp.Compile(rs.RegexPattern)
 
@@ -112,23 +191,33 @@ func (rs regexSelector) asContentSelector() (contentSelector, error) {
 		return nil, fmt.Errorf("ERROR: Regex %s for selector did not compile. Error is %s", rs.RegexPattern, err.Error())
 	}
 
-	return func(c content) content {
-		result := compiledRegex.FindStringSubmatch(c.OriginalText)
+	return func(c content) []content {
+		matchCount := 1
+		if rs.AllMatches {
+			matchCount = -1
+		}
+
+		blocks := make([]content, 0)
 
-		for k, v := range result {
-			if int64(k) == rs.GroupNumber {
-				newContent := content{OriginalText: v}
-				newContent.prepare()
-				return newContent
+		for _, result := range compiledRegex.FindAllStringSubmatchIndex(c.OriginalText, matchCount) {
+			for k := 0; k < len(result)/2; k++ {
+				if int64(k) == rs.GroupNumber {
+					start, end := maxInt(result[2*k], 0), maxInt(result[2*k+1], 0)
+					blocks = append(blocks, newSelectedContentAt(c.OriginalText[start:end], start))
+				}
 			}
 		}
 
-		return content{OriginalText: ""}
+		if len(blocks) == 0 && !rs.AllMatches {
+			return []content{{OriginalText: ""}}
+		}
+
+		return blocks
 	}, nil
 }
 
 func (lns lineNumberSelector) asContentSelector() (contentSelector, error) {
-	return func(c content) content {
+	return func(c content) []content {
 		lines := strings.Split(c.OriginalText, "\n")
 
 		if lns.FromLine == -1 {
@@ -140,45 +229,202 @@ func (lns lineNumberSelector) asContentSelector() (contentSelector, error) {
 		}
 
 		selectedLines := strings.Join(lines[lns.FromLine-1:lns.ToLine], "\n")
-		newContent := content{
-			OriginalText: selectedLines,
+		return []content{newSelectedContentAt(selectedLines, lineOffset(lines, int(lns.FromLine-1)))}
+	}, nil
+}
+
+func (cs columnSelector) asContentSelector() (contentSelector, error) {
+	if cs.ToColumn != -1 && cs.FromColumn > cs.ToColumn {
+		return nil, fmt.Errorf("ERROR: Column selector fromColumn %d is after toColumn %d", cs.FromColumn, cs.ToColumn)
+	}
+
+	return func(c content) []content {
+		lines := strings.Split(c.OriginalText, "\n")
+		selectedLines := make([]string, 0, len(lines))
+
+		for _, line := range lines {
+			runes := []rune(line)
+			fromColumn, toColumn := cs.FromColumn, cs.ToColumn
+
+			if fromColumn < 1 {
+				fromColumn = 1
+			}
+
+			if toColumn > int64(len(runes)) || toColumn == -1 {
+				toColumn = int64(len(runes))
+			}
+
+			if fromColumn > toColumn {
+				selectedLines = append(selectedLines, "")
+				continue
+			}
+
+			selectedLines = append(selectedLines, string(runes[fromColumn-1:toColumn]))
 		}
-		newContent.prepare()
-		return newContent
+
+		return []content{newSelectedContent(strings.Join(selectedLines, "\n"))}
 	}, nil
 }
 
 func (tbs textBlockSelector) asContentSelector() (contentSelector, error) {
-	return func(c content) content {
-		var fromIndex, toIndex int
+	if tbs.Occurrence < 1 {
+		return nil, fmt.Errorf("ERROR: Text block selector occurrence should be 1 or more but was %d", tbs.Occurrence)
+	}
+
+	fromMarker, err := tbs.compileMarker(tbs.FromText, tbs.FromRegex)
+
+	if err != nil {
+		return nil, err
+	}
+
+	toMarker, err := tbs.compileMarker(tbs.ToText, tbs.ToRegex)
+
+	if err != nil {
+		return nil, err
+	}
 
+	return func(c content) []content {
 		if len(c.OriginalText) < 1 {
-			return content{OriginalText: ""}
+			return []content{{OriginalText: ""}}
 		}
 
-		fromIndex = strings.Index(c.OriginalText, tbs.FromText)
-		if fromIndex == -1 || tbs.FromText == "" {
-			fromIndex = 0
+		if !tbs.AllBlocks {
+			selectedBlock, _, err := tbs.selectBlock(c.OriginalText, 0, int(tbs.Occurrence), fromMarker, toMarker)
+			if err != nil {
+				return []content{{OriginalText: "", SelectionError: err}}
+			}
+			return []content{selectedBlock}
 		}
 
-		toIndex = strings.Index(c.OriginalText, tbs.ToText)
-		if toIndex == -1 || tbs.ToText == "" {
-			toIndex = len(c.OriginalText) - 1
-		}
+		blocks := make([]content, 0)
 
-		if toIndex > len(c.OriginalText) {
-			toIndex = len(c.OriginalText) - 1
+		for searchFrom := 0; searchFrom < len(c.OriginalText); {
+			selectedBlock, blockEnd, err := tbs.selectBlock(c.OriginalText, searchFrom, 1, fromMarker, toMarker)
+			if err != nil || blockEnd <= searchFrom {
+				break
+			}
+			blocks = append(blocks, selectedBlock)
+			searchFrom = blockEnd
 		}
 
-		newContent := content{OriginalText: c.OriginalText[fromIndex:toIndex]}
-		newContent.prepare()
+		if len(blocks) == 0 && tbs.Required {
+			return []content{{OriginalText: "", SelectionError: fmt.Errorf("ERROR: Text block selector could not find any block")}}
+		}
 
-		return newContent
+		return blocks
 	}, nil
 }
 
+//selectBlock selects the block starting at the nth occurrence of the start marker after searchFrom. The end marker is only searched after
+//the start marker. It returns the block and the index where searching for the next block can resume. Missing markers fall back to the
+//start or the end of the text, unless the selector is required or the start marker is searched for more than one block.
+func (tbs textBlockSelector) selectBlock(text string, searchFrom int, occurrence int, fromMarker, toMarker *regexp.Regexp) (content, int, error) {
+	fromIndex, fromEnd, toIndex, nextIndex := searchFrom, searchFrom, len(text), len(text)
+
+	if fromMarker != nil {
+		if location := nthMatch(fromMarker, text[searchFrom:], occurrence); location != nil {
+			fromIndex, fromEnd = searchFrom+location[0], searchFrom+location[1]
+			if !tbs.IncludeFrom {
+				fromIndex = fromEnd
+			}
+		} else if tbs.Required || tbs.AllBlocks {
+			return content{}, 0, fmt.Errorf("ERROR: Text block selector could not find start marker %s", fromMarker.String())
+		}
+	}
+
+	if toMarker != nil {
+		if location := toMarker.FindStringIndex(text[fromEnd:]); location != nil {
+			toIndex, nextIndex = fromEnd+location[0], fromEnd+location[0]
+			if tbs.IncludeTo {
+				toIndex = fromEnd + location[1]
+			}
+			if fromMarker == nil {
+				nextIndex = fromEnd + location[1]
+			}
+		} else if tbs.Required {
+			return content{}, 0, fmt.Errorf("ERROR: Text block selector could not find end marker %s", toMarker.String())
+		}
+	}
+
+	return newSelectedContentAt(text[fromIndex:toIndex], fromIndex), nextIndex, nil
+}
+
+//compileMarker turns the literal text or the regex of a marker into a regex. A nil regex is returned when neither is configured.
+func (tbs textBlockSelector) compileMarker(text string, pattern string) (*regexp.Regexp, error) {
+	if text == "" && pattern == "" {
+		return nil, nil
+	}
+
+	if text != "" {
+		pattern = regexp.QuoteMeta(text)
+	}
+
+	if tbs.CaseInsensitive {
+		pattern = "(?i)" + pattern
+	}
+
+	compiledRegex, err := regexp.Compile(pattern)
+
+	if err != nil {
+		return nil, fmt.Errorf("ERROR: Regex %s for text block selector did not compile. Error is %s", pattern, err.Error())
+	}
+
+	return compiledRegex, nil
+}
+
+//nthMatch returns the start and end index of the nth match of the regex in text, or nil when there are fewer matches
+func nthMatch(compiledRegex *regexp.Regexp, text string, occurrence int) []int {
+	matches := compiledRegex.FindAllStringIndex(text, occurrence)
+
+	if len(matches) < occurrence {
+		return nil
+	}
+
+	return matches[occurrence-1]
+}
+
 func (cs contentSelector) addNestedSelector(wrappingSelector contentSelector) contentSelector {
-	return func(c content) content {
-		return wrappingSelector(cs(c))
+	return func(c content) []content {
+		selectedBlocks := make([]content, 0)
+
+		for _, selectedContent := range cs(c) {
+			if selectedContent.SelectionError != nil {
+				return []content{selectedContent}
+			}
+
+			selectedContent = c.locate(selectedContent)
+
+			for _, nestedContent := range wrappingSelector(selectedContent) {
+				nestedContent = selectedContent.locate(nestedContent)
+				nestedContent.Offset += selectedContent.Offset
+				selectedBlocks = append(selectedBlocks, nestedContent)
+			}
+		}
+
+		return selectedBlocks
+	}
+}
+
+func newSelectedContent(text string) content {
+	newContent := content{OriginalText: text}
+	newContent.prepare()
+	return newContent
+}
+
+//newSelectedContentAt creates the content for text selected at offset in the content it was selected from
+func newSelectedContentAt(text string, offset int) content {
+	newContent := newSelectedContent(text)
+	newContent.Offset = offset
+	return newContent
+}
+
+//lineOffset returns the offset of the line at index in the text the lines were split from
+func lineOffset(lines []string, index int) int {
+	offset := 0
+
+	for _, line := range lines[:minInt(index, len(lines))] {
+		offset += len(line) + 1
 	}
+
+	return offset
 }
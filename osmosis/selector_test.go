@@ -17,7 +17,7 @@ func TestThatTextBlockSelectorSelectsItCorrectly(t *testing.T) {
 
 	selector, _ := classifyAndBuildSelector([]byte(positiveSelector))
 
-	selectedContent := selector(c)
+	selectedContent := selector(c)[0]
 
 	if strings.Compare(selectedContent.SanitizedText, expectedOutput) != 0 {
 		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedOutput)
@@ -36,7 +36,7 @@ func TestThatTextBlockSelectorSelectsWhenItChoosesFirstAndLastWord(t *testing.T)
 
 	selector, _ := classifyAndBuildSelector([]byte(positiveSelector))
 
-	selectedContent := selector(c)
+	selectedContent := selector(c)[0]
 
 	if strings.Compare(selectedContent.SanitizedText, expectedOutput) != 0 {
 		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedOutput)
@@ -55,7 +55,7 @@ func TestThatTextBlockSelectorSelectsTillTheEndWhenTillTextIsNotPresent(t *testi
 
 	selector, _ := classifyAndBuildSelector([]byte(positiveSelector))
 
-	selectedContent := selector(c)
+	selectedContent := selector(c)[0]
 
 	if strings.Compare(selectedContent.SanitizedText, expectedOutput) != 0 {
 		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedOutput)
@@ -74,7 +74,7 @@ func TestThatTextBlockSelectorSelectsFromBeginningWhenFromTextIsNotPresent(t *te
 
 	selector, _ := classifyAndBuildSelector([]byte(positiveSelector))
 
-	selectedContent := selector(c)
+	selectedContent := selector(c)[0]
 
 	if strings.Compare(selectedContent.SanitizedText, expectedOutput) != 0 {
 		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedOutput)
@@ -93,7 +93,7 @@ func TestThatContentSelectorForTextBlockSelectorSelectsFromBeginningToEndwhenBot
 
 	selector, _ := classifyAndBuildSelector([]byte(positiveSelector))
 
-	selectedContent := selector(c)
+	selectedContent := selector(c)[0]
 
 	if strings.Compare(selectedContent.SanitizedText, expectedOutput) != 0 {
 		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedOutput)
@@ -110,13 +110,128 @@ func TestThatTextBlockSelectorSelectsFromBeginningToEndwhenFromAndTillTextAreNot
 
 	selector, _ := classifyAndBuildSelector([]byte(positiveSelector))
 
-	selectedContent := selector(c)
+	selectedContent := selector(c)[0]
 
 	if strings.Compare(selectedContent.SanitizedText, expectedOutput) != 0 {
 		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedOutput)
 	}
 }
 
+func TestThatTextBlockSelectorSelectsTillTheLastCharacterWhenToTextIsNotPresent(t *testing.T) {
+	selector, _ := classifyAndBuildSelector([]byte(`{
+		"selectorType": "textBlockSelector",
+		"fromText" : "Authorised",
+		"toText": "NotPresent"
+	}`))
+	c := content{OriginalText: "Total 140.0\nAuthorised Signatory"}
+	c.prepare()
+
+	selectedContent := selector(c)[0]
+
+	if strings.Compare(selectedContent.OriginalText, "Authorised Signatory") != 0 {
+		t.Errorf("Expected selected text [%s] to match [Authorised Signatory]", selectedContent.OriginalText)
+	}
+}
+
+func TestThatTextBlockSelectorSearchesToTextOnlyAfterFromText(t *testing.T) {
+	expectedOutput := "Convenience Fee Play Convenience Fee8 CGST 9.0 SGST 9.0"
+	selector, _ := classifyAndBuildSelector([]byte(`{
+		"selectorType": "textBlockSelector",
+		"fromText" : "Convenience Fee (Play",
+		"toText": "Total"
+	}`))
+	c := content{OriginalText: contentString}
+	c.prepare()
+
+	selectedContent := selector(c)[0]
+
+	if strings.Compare(selectedContent.SanitizedText, expectedOutput) != 0 {
+		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedOutput)
+	}
+}
+
+func TestThatTextBlockSelectorCanExcludeFromTextAndIncludeToText(t *testing.T) {
+	expectedOutput := "Jacob Description"
+	selector, _ := classifyAndBuildSelector([]byte(`{
+		"selectorType": "textBlockSelector",
+		"fromText" : "Customer Name",
+		"toText": "Description",
+		"includeFrom": false,
+		"includeTo": true
+	}`))
+	c := content{OriginalText: contentString}
+	c.prepare()
+
+	selectedContent := selector(c)[0]
+
+	if strings.Compare(selectedContent.SanitizedText, expectedOutput) != 0 {
+		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedOutput)
+	}
+}
+
+func TestThatTextBlockSelectorSelectsFromNthOccurrenceOfFromText(t *testing.T) {
+	expectedOutput := "Convenience Fee Ride"
+	selector, _ := classifyAndBuildSelector([]byte(`{
+		"selectorType": "textBlockSelector",
+		"fromText" : "convenience fee",
+		"toText": "\n",
+		"occurrence": 2,
+		"caseInsensitive": true
+	}`))
+	c := content{OriginalText: contentString}
+	c.prepare()
+
+	selectedContent := selector(c)[0]
+
+	if strings.Compare(selectedContent.SanitizedText, expectedOutput) != 0 {
+		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedOutput)
+	}
+}
+
+func TestThatTextBlockSelectorSupportsRegexMarkers(t *testing.T) {
+	expectedOutput := "CGST 9.0 SGST 9.0"
+	selector, err := classifyAndBuildSelector([]byte(`{
+		"selectorType": "textBlockSelector",
+		"fromRegex" : "[CS]GST\s+\d",
+		"toRegex": "(?m)^Total"
+	}`))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	c := content{OriginalText: contentString}
+	c.prepare()
+
+	selectedContent := selector(c)[0]
+
+	if strings.Compare(selectedContent.SanitizedText, expectedOutput) != 0 {
+		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedOutput)
+	}
+}
+
+func TestThatRequiredTextBlockSelectorReturnsErrorWhenMarkerIsMissing(t *testing.T) {
+	selector, _ := classifyAndBuildSelector([]byte(`{
+		"selectorType": "textBlockSelector",
+		"fromText" : "Customer Name",
+		"toText": "NotPresent",
+		"required": true,
+		"contentSelector" : {
+			"selectorType": "lineNumberSelector",
+			"fromLine": 1,
+			"toLine": 1
+		}
+	}`))
+	c := content{OriginalText: contentString}
+	c.prepare()
+
+	selectedContent := selector(c)[0]
+
+	if selectedContent.SelectionError == nil {
+		t.Errorf("Expected a selection error but got selected text [%s]", selectedContent.OriginalText)
+	}
+}
+
 func TestThatLineSelectorWillSelectBetweenSpecifiedLines(t *testing.T) {
 	expectedOutput := "Customer Name Jacob Description"
 	positiveSelector := `{
@@ -129,7 +244,7 @@ func TestThatLineSelectorWillSelectBetweenSpecifiedLines(t *testing.T) {
 
 	selector, _ := classifyAndBuildSelector([]byte(positiveSelector))
 
-	selectedContent := selector(c)
+	selectedContent := selector(c)[0]
 
 	if strings.Compare(selectedContent.SanitizedText, expectedOutput) != 0 {
 		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedOutput)
@@ -148,7 +263,7 @@ func TestThatLineSelectorWillSelectTillLastLineWhenToLineIsOutOfBounds(t *testin
 
 	selector, _ := classifyAndBuildSelector([]byte(positiveSelector))
 
-	selectedContent := selector(c)
+	selectedContent := selector(c)[0]
 
 	if strings.Compare(selectedContent.SanitizedText, expectedOutput) != 0 {
 		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedOutput)
@@ -165,7 +280,7 @@ func TestThatLineSelectorWillSelectFromFirstLineWhenFromLineIsMissing(t *testing
 
 	selector, _ := classifyAndBuildSelector([]byte(positiveSelector))
 
-	selectedContent := selector(c)
+	selectedContent := selector(c)[0]
 
 	if strings.Compare(selectedContent.OriginalText, contentString) != 0 {
 		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, contentString)
@@ -181,7 +296,7 @@ func TestThatLineSelectorWillSelectFromStartTillLastLineWhenToLineIsMissing(t *t
 
 	selector, _ := classifyAndBuildSelector([]byte(positiveSelector))
 
-	selectedContent := selector(c)
+	selectedContent := selector(c)[0]
 
 	if strings.Compare(selectedContent.OriginalText, contentString) != 0 {
 		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, contentString)
@@ -205,9 +320,130 @@ func TestThatNestedSelectorCanBeProvidedAsPartOfConfig(t *testing.T) {
 
 	selector, _ := classifyAndBuildSelector([]byte(contentSelector))
 
-	selectedContent := selector(c)
+	selectedContent := selector(c)[0]
 
 	if strings.Compare(selectedContent.SanitizedText, expectedText) != 0 {
 		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.SanitizedText, expectedText)
 	}
 }
+
+func TestThatColumnSelectorSelectsCharacterColumnsOfEveryLine(t *testing.T) {
+	expectedText := "Qty\n₹₹1\n  2"
+	contentSelector := `{
+		"selectorType": "columnSelector",
+		"fromColumn": 12,
+		"toColumn": 14
+	}`
+	c := content{OriginalText: "Item       Qty  Rate\nChai       ₹₹1  20.5\nBun          2"}
+	c.prepare()
+
+	selector, err := classifyAndBuildSelector([]byte(contentSelector))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	selectedContent := selector(c)[0]
+
+	if strings.Compare(selectedContent.OriginalText, expectedText) != 0 {
+		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.OriginalText, expectedText)
+	}
+}
+
+func TestThatColumnSelectorSelectsTillEndOfLineWhenToColumnIsMissing(t *testing.T) {
+	expectedText := "Rate\n20.5\n"
+	contentSelector := `{
+		"selectorType": "lineNumberSelector",
+		"contentSelector": {
+			"selectorType": "columnSelector",
+			"fromColumn": 17
+		}
+	}`
+	c := content{OriginalText: "Item       Qty  Rate\nChai       1    20.5\nBun          2"}
+	c.prepare()
+
+	selector, _ := classifyAndBuildSelector([]byte(contentSelector))
+
+	selectedContent := selector(c)[0]
+
+	if strings.Compare(selectedContent.OriginalText, expectedText) != 0 {
+		t.Errorf("Expected selected text [%s] to match [%s]", selectedContent.OriginalText, expectedText)
+	}
+}
+
+func TestThatRegexSelectorSelectsEveryMatchWhenAllIsSet(t *testing.T) {
+	selector, _ := classifyAndBuildSelector([]byte(`{
+		"selectorType": "regexSelector",
+		"regex" : "(?m)^([CS]GST\s+[\d.%]+)$",
+		"groupNumber": 1,
+		"all": true
+	}`))
+	c := content{OriginalText: contentString}
+	c.prepare()
+
+	selectedBlocks := selector(c)
+
+	if len(selectedBlocks) != 2 || selectedBlocks[0].OriginalText != "CGST 9.0%" || selectedBlocks[1].OriginalText != "SGST 9.0%" {
+		t.Errorf("Expected both tax lines to be selected but got %v", selectedBlocks)
+	}
+}
+
+func TestThatTextBlockSelectorSelectsEveryBlockWhenAllIsSet(t *testing.T) {
+	selector, _ := classifyAndBuildSelector([]byte(`{
+		"selectorType": "textBlockSelector",
+		"fromText" : "Convenience Fee (",
+		"toText": "\n",
+		"includeFrom": false,
+		"all": true
+	}`))
+	c := content{OriginalText: contentString}
+	c.prepare()
+
+	selectedBlocks := selector(c)
+
+	if len(selectedBlocks) != 2 || selectedBlocks[0].OriginalText != "Ride)" || selectedBlocks[1].OriginalText != "Play Convenience Fee(8%))" {
+		t.Errorf("Expected both fee lines to be selected but got %v", selectedBlocks)
+	}
+}
+
+func TestThatNestedSelectorRunsOnEverySelectedBlock(t *testing.T) {
+	selector, _ := classifyAndBuildSelector([]byte(`{
+		"selectorType": "textBlockSelector",
+		"fromRegex" : "(?m)^[CS]GST",
+		"toText": "%",
+		"all": true,
+		"contentSelector" : {
+			"selectorType": "regexSelector",
+			"regex": "[\d.]+",
+			"groupNumber": 0
+		}
+	}`))
+	c := content{OriginalText: contentString}
+	c.prepare()
+
+	selectedBlocks := selector(c)
+
+	if len(selectedBlocks) != 2 || selectedBlocks[0].OriginalText != "9.0" || selectedBlocks[1].OriginalText != "9.0" {
+		t.Errorf("Expected tax rates of both blocks to be selected but got %v", selectedBlocks)
+	}
+}
+
+func TestThatInvalidSelectorsAreReportedWhenConfigIsLoaded(t *testing.T) {
+	invalidSelectors := []string{
+		`{"selectorType": "anchorSelector"}`,
+		`{"selectorType": "tableSelector"}`,
+		`{"selectorType": "textBlockSelector", "fromRegex": "("}`,
+		`{"selectorType": "nopeSelector"}`,
+		`{"selectorType": "lineNumberSelector", "fromLine": 1, "toLine": 1, "contentSelector": {"selectorType": "anchorSelector"}}`,
+	}
+
+	for _, selector := range invalidSelectors {
+		config := `{"templates": [{"templateName": "Ola", "matchers": {"matcherType": "oneWordMatcher", "words": "ANI"},
+			"sections": [{"contentSelector": ` + selector + `,
+			"contentExtractors": [{"extractorType": "regexExtractor", "regex": "(\w+)", "attributeName": "word", "groupNumber": 1}]}]}]}`
+
+		if _, err := LoadConfig(strings.NewReader(config)); err == nil {
+			t.Errorf("Expected an error for selector %s", selector)
+		}
+	}
+}
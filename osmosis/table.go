@@ -0,0 +1,358 @@
+package osmosis
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/buger/jsonparser"
+)
+
+//tableLayout describes the columns of a table selected by a tableSelector. Column positions are rune offsets within a line.
+//Fixed width columns are sliced strictly by position, otherwise text is assigned to the column it overlaps the most.
+type tableLayout struct {
+	Columns    []tableColumn
+	FixedWidth bool
+}
+
+type tableColumn struct {
+	Name  string
+	Start int
+	End   int
+}
+
+type tableSelector struct {
+	Headers         []string
+	HeaderRegex     string
+	Columns         []tableColumn
+	EndRegex        string
+	StopAtBlankLine bool
+}
+
+type tableExtractor struct {
+	AttributeName string
+	RowColumn     string
+}
+
+//cellChunk is a run of text in a line, separated from its neighbours by at least two spaces
+type cellChunk struct {
+	Text  string
+	Start int
+	End   int
+}
+
+func getTableSelector(value []byte) tableSelector {
+	headerRegex, _, _, _ := jsonparser.Get(value, "headerRegex")
+	endRegex, _, _, _ := jsonparser.Get(value, "endRegex")
+	stopAtBlankLine, _ := jsonparser.GetBoolean(value, "stopAtBlankLine")
+	columns := make([]tableColumn, 0)
+
+	jsonparser.ArrayEach(value, func(column []byte, dataType jsonparser.ValueType, offset int, err error) {
+		name, _ := jsonparser.GetString(column, "name")
+		fromColumn, _ := jsonparser.GetInt(column, "fromColumn")
+		toColumn, _ := jsonparser.GetInt(column, "toColumn")
+		columns = append(columns, tableColumn{Name: name, Start: int(fromColumn) - 1, End: int(toColumn)})
+	}, "columns")
+
+	return tableSelector{
+		Headers:         getStringList(value, "headers"),
+		HeaderRegex:     string(headerRegex),
+		Columns:         columns,
+		EndRegex:        string(endRegex),
+		StopAtBlankLine: stopAtBlankLine,
+	}
+}
+
+func (ts tableSelector) asContentSelector() (contentSelector, error) {
+	var headerRegex, endRegex *regexp.Regexp
+	var err error
+
+	if len(ts.Headers) == 0 && ts.HeaderRegex == "" && len(ts.Columns) == 0 {
+		return nil, fmt.Errorf("ERROR: Table selector requires headers, a headerRegex or columns to be configured")
+	}
+
+	if ts.HeaderRegex != "" {
+		if headerRegex, err = regexp.Compile(ts.HeaderRegex); err != nil {
+			return nil, fmt.Errorf("ERROR: Header regex %s for table selector did not compile. Error is %s", ts.HeaderRegex, err.Error())
+		}
+	}
+
+	if ts.EndRegex != "" {
+		if endRegex, err = regexp.Compile(ts.EndRegex); err != nil {
+			return nil, fmt.Errorf("ERROR: End regex %s for table selector did not compile. Error is %s", ts.EndRegex, err.Error())
+		}
+	}
+
+	for _, column := range ts.Columns {
+		if column.Name == "" || column.Start < 0 || column.End <= column.Start {
+			return nil, fmt.Errorf("ERROR: Table selector column %s should have a name and fromColumn <= toColumn", column.Name)
+		}
+	}
+
+	return func(c content) []content {
+		lines := strings.Split(c.OriginalText, "\n")
+		headerIndex := -1
+		layout := tableLayout{Columns: ts.Columns, FixedWidth: len(ts.Columns) > 0}
+
+		for index, line := range lines {
+			if columns, found := ts.matchHeader(line, headerRegex); found {
+				headerIndex = index
+				if !layout.FixedWidth {
+					layout.Columns = columns
+				}
+				break
+			}
+		}
+
+		if headerIndex == -1 && (headerRegex != nil || len(ts.Headers) > 0) {
+			return []content{{OriginalText: ""}}
+		}
+
+		tableLines := make([]string, 0)
+
+		for index := headerIndex + 1; index < len(lines); index++ {
+			if endRegex != nil && endRegex.MatchString(lines[index]) {
+				break
+			}
+			if ts.StopAtBlankLine && strings.TrimSpace(lines[index]) == "" && len(tableLines) > 0 {
+				break
+			}
+			tableLines = append(tableLines, lines[index])
+		}
+
+		newContent := content{OriginalText: strings.Join(tableLines, "\n"), Table: &layout, Offset: lineOffset(lines, headerIndex+1)}
+		newContent.prepare()
+		return []content{newContent}
+	}, nil
+}
+
+//matchHeader checks whether the line is the header row and returns the columns found in it
+func (ts tableSelector) matchHeader(line string, headerRegex *regexp.Regexp) ([]tableColumn, bool) {
+	if headerRegex != nil {
+		if !headerRegex.MatchString(line) {
+			return nil, false
+		}
+
+		columns := make([]tableColumn, 0)
+		for _, chunk := range cellChunks(line) {
+			columns = append(columns, tableColumn{Name: chunk.Text, Start: chunk.Start, End: chunk.End})
+		}
+		return columns, true
+	}
+
+	if len(ts.Headers) == 0 {
+		return nil, false
+	}
+
+	runes := []rune(strings.ToLower(line))
+	columns := make([]tableColumn, 0, len(ts.Headers))
+	searchFrom := 0
+
+	for _, header := range ts.Headers {
+		headerRunes := []rune(strings.ToLower(header))
+		index := runeIndex(runes[searchFrom:], headerRunes)
+
+		if index == -1 {
+			return nil, false
+		}
+
+		start := searchFrom + index
+		columns = append(columns, tableColumn{Name: header, Start: start, End: start + len(headerRunes)})
+		searchFrom = start + len(headerRunes)
+	}
+
+	return columns, true
+}
+
+func getTableExtractor(value []byte) tableExtractor {
+	attributeName, _ := jsonparser.GetString(value, "attributeName")
+	rowColumn, _ := jsonparser.GetString(value, "rowColumn")
+
+	return tableExtractor{
+		AttributeName: attributeName,
+		RowColumn:     rowColumn,
+	}
+}
+
+func (te tableExtractor) asContentExtractor() (contentExtractor, error) {
+	if te.AttributeName == "" {
+		return nil, fmt.Errorf("ERROR: Table extractor requires an attributeName")
+	}
+
+	return func(c content) []ExtractedContent {
+		lines := strings.Split(c.OriginalText, "\n")
+		var layout tableLayout
+
+		if c.Table != nil {
+			layout = *c.Table
+		} else {
+			layout, lines = detectTableLayout(lines)
+		}
+
+		if len(layout.Columns) == 0 {
+			return []ExtractedContent{{AttributeName: te.AttributeName + ".count", AttributeValue: "0"}}
+		}
+
+		rows := layout.rows(lines, te.rowColumnIndex(layout))
+		extracted := make([]ExtractedContent, 0, len(rows)*len(layout.Columns)+1)
+
+		for rowIndex, row := range rows {
+			for columnIndex, column := range layout.Columns {
+				extracted = append(extracted, ExtractedContent{
+					AttributeName:  fmt.Sprintf("%s.%d.%s", te.AttributeName, rowIndex+1, normalizeKey(column.Name, camelCaseKeys)),
+					AttributeValue: row[columnIndex],
+				})
+			}
+		}
+
+		return append(extracted, ExtractedContent{AttributeName: te.AttributeName + ".count", AttributeValue: strconv.Itoa(len(rows))})
+	}, nil
+}
+
+//rowColumnIndex is the column that has a value on the first line of every row. Lines without it continue the previous row.
+func (te tableExtractor) rowColumnIndex(layout tableLayout) int {
+	for index, column := range layout.Columns {
+		if te.RowColumn != "" && (strings.EqualFold(column.Name, te.RowColumn) || normalizeKey(column.Name, camelCaseKeys) == te.RowColumn) {
+			return index
+		}
+	}
+
+	return len(layout.Columns) - 1
+}
+
+//detectTableLayout treats the first non empty line as the header row when the selector did not describe the table
+func detectTableLayout(lines []string) (tableLayout, []string) {
+	for index, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		columns := make([]tableColumn, 0)
+		for _, chunk := range cellChunks(line) {
+			columns = append(columns, tableColumn{Name: chunk.Text, Start: chunk.Start, End: chunk.End})
+		}
+		return tableLayout{Columns: columns}, lines[index+1:]
+	}
+
+	return tableLayout{}, lines
+}
+
+//rows splits the lines into cells and merges wrapped lines into the row they belong to
+func (tl tableLayout) rows(lines []string, rowColumn int) [][]string {
+	rows := make([][]string, 0)
+	pending := make([]string, len(tl.Columns))
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		cells := tl.cells(line)
+
+		if cells[rowColumn] != "" {
+			rows = append(rows, mergeCells(pending, cells))
+			pending = make([]string, len(tl.Columns))
+		} else if len(rows) > 0 {
+			rows[len(rows)-1] = mergeCells(rows[len(rows)-1], cells)
+		} else {
+			pending = mergeCells(pending, cells)
+		}
+	}
+
+	return rows
+}
+
+func (tl tableLayout) cells(line string) []string {
+	cells := make([]string, len(tl.Columns))
+	runes := []rune(line)
+
+	if tl.FixedWidth {
+		for index, column := range tl.Columns {
+			if column.Start < len(runes) {
+				cells[index] = strings.TrimSpace(string(runes[column.Start:minInt(column.End, len(runes))]))
+			}
+		}
+		return cells
+	}
+
+	for _, chunk := range cellChunks(line) {
+		index := tl.closestColumn(chunk)
+		cells[index] = strings.TrimSpace(cells[index] + " " + chunk.Text)
+	}
+
+	return cells
+}
+
+//closestColumn is the column whose header overlaps the chunk the most, or the nearest one when none overlaps
+func (tl tableLayout) closestColumn(chunk cellChunk) int {
+	bestIndex, bestOverlap, bestGap := 0, 0, -1
+
+	for index, column := range tl.Columns {
+		overlap := minInt(chunk.End, column.End) - maxInt(chunk.Start, column.Start)
+
+		if overlap > bestOverlap {
+			bestIndex, bestOverlap = index, overlap
+			continue
+		}
+
+		gap := maxInt(column.Start-chunk.End, chunk.Start-column.End)
+		if bestOverlap == 0 && (bestGap == -1 || gap < bestGap) {
+			bestIndex, bestGap = index, gap
+		}
+	}
+
+	return bestIndex
+}
+
+func cellChunks(line string) []cellChunk {
+	chunks := make([]cellChunk, 0)
+	runes := []rune(line)
+	start := -1
+
+	for index := 0; index <= len(runes); index++ {
+		atSeparator := index == len(runes) || runes[index] == '\t' ||
+			(unicode.IsSpace(runes[index]) && index+1 < len(runes) && unicode.IsSpace(runes[index+1])) ||
+			(unicode.IsSpace(runes[index]) && index+1 == len(runes))
+
+		if !atSeparator && start == -1 && !unicode.IsSpace(runes[index]) {
+			start = index
+		}
+
+		if atSeparator && start != -1 {
+			text := strings.TrimSpace(string(runes[start:index]))
+			chunks = append(chunks, cellChunk{Text: text, Start: start, End: start + len([]rune(text))})
+			start = -1
+		}
+	}
+
+	return chunks
+}
+
+func mergeCells(existing []string, additional []string) []string {
+	merged := make([]string, len(existing))
+
+	for index := range existing {
+		merged[index] = strings.TrimSpace(existing[index] + " " + additional[index])
+	}
+
+	return merged
+}
+
+func runeIndex(text []rune, search []rune) int {
+	for index := 0; index+len(search) <= len(text); index++ {
+		if string(text[index:index+len(search)]) == string(search) {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func maxInt(first, second int) int {
+	if first > second {
+		return first
+	}
+	return second
+}
@@ -0,0 +1,85 @@
+package osmosis
+
+import (
+	"testing"
+)
+
+var tableText = `ORDER DETAILS
+Item                          Qty     Rate     Amount
+Teriyaki Chicken Rice Bowl      1    140.0      140.0
+(Non Veg)
+Masala Chai                     2     20.5       41.0
+Brownie with a very long        1     99.0       99.0
+  name that wraps
+Total                                           280.0
+`
+
+func TestThatTableSelectorDetectsColumnsFromHeaderRow(t *testing.T) {
+	c := content{OriginalText: tableText}
+	c.prepare()
+
+	selector, _ := classifyAndBuildSelector([]byte(`{
+		"selectorType": "tableSelector",
+		"headers": ["Item", "Qty", "Rate", "Amount"],
+		"endRegex": "^Total"
+	}`))
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "tableExtractor", "attributeName": "items"}`))
+	values := attributeValues(extractor(selector(c)[0]))
+
+	expected := map[string]string{
+		"items.count":    "3",
+		"items.1.item":   "Teriyaki Chicken Rice Bowl (Non Veg)",
+		"items.1.qty":    "1",
+		"items.1.amount": "140.0",
+		"items.2.item":   "Masala Chai",
+		"items.2.rate":   "20.5",
+		"items.3.item":   "Brownie with a very long name that wraps",
+		"items.3.amount": "99.0",
+	}
+
+	for key, value := range expected {
+		if values[key] != value {
+			t.Errorf("Expected %s to be [%s] but got [%s]", key, value, values[key])
+		}
+	}
+}
+
+func TestThatTableSelectorUsesConfiguredColumnPositions(t *testing.T) {
+	c := content{OriginalText: tableText}
+	c.prepare()
+
+	selector, _ := classifyAndBuildSelector([]byte(`{
+		"selectorType": "tableSelector",
+		"headerRegex": "^Item\s+Qty",
+		"endRegex": "^Total",
+		"columns": [
+			{ "name": "description", "fromColumn": 1, "toColumn": 30 },
+			{ "name": "quantity", "fromColumn": 31, "toColumn": 35 },
+			{ "name": "total", "fromColumn": 45, "toColumn": 60 }
+		]
+	}`))
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "tableExtractor", "attributeName": "items"}`))
+	values := attributeValues(extractor(selector(c)[0]))
+
+	if values["items.count"] != "3" || values["items.2.description"] != "Masala Chai" || values["items.2.quantity"] != "2" || values["items.2.total"] != "41.0" {
+		t.Errorf("Expected fixed width columns to be sliced by position but got %v", values)
+	}
+}
+
+func TestThatTableExtractorDetectsHeaderWithoutTableSelector(t *testing.T) {
+	extractor, _ := classifyAndBuildExtractor([]byte(`{"extractorType": "tableExtractor", "attributeName": "fares", "rowColumn": "fare"}`))
+	c := content{OriginalText: "Component      Fare\nBase Fare      100.0\nTolls           20.0"}
+	c.prepare()
+
+	values := attributeValues(extractor(c))
+
+	if values["fares.count"] != "2" || values["fares.2.component"] != "Tolls" || values["fares.2.fare"] != "20.0" {
+		t.Errorf("Expected the first line to be used as header but got %v", values)
+	}
+}
+
+func TestThatTableSelectorRequiresHeadersOrColumns(t *testing.T) {
+	if _, err := getTableSelector([]byte(`{"selectorType": "tableSelector"}`)).asContentSelector(); err == nil {
+		t.Errorf("Expected an error when neither headers nor columns are configured")
+	}
+}
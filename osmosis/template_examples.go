@@ -0,0 +1,198 @@
+package osmosis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/buger/jsonparser"
+)
+
+//templateExample is a sample document for a template along with the attributes it is expected to extract. The document is either
+//read from File or given inline as Text.
+type templateExample struct {
+	File     string
+	Text     string
+	Expected []expectedAttribute
+}
+
+type expectedAttribute struct {
+	Name  string
+	Value string
+}
+
+//ExampleResult is the outcome of running ParseText over one example of a template. Err is set when the example could not be read,
+//could not be parsed or was not matched by its template.
+type ExampleResult struct {
+	TemplateName string
+	Example      string
+	Diffs        []AttributeDiff
+	Err          error
+}
+
+//AttributeDiff describes an expected attribute that was not extracted with the expected value. Found is false when the attribute
+//was not extracted at all. Actual lists every value extracted for the attribute, separated by commas.
+type AttributeDiff struct {
+	AttributeName string
+	Expected      string
+	Actual        string
+	Found         bool
+}
+
+//ExampleReport holds the results of every example of every template, ordered by template name and then by example
+type ExampleReport struct {
+	Results []ExampleResult
+}
+
+func (ad AttributeDiff) String() string {
+	if !ad.Found {
+		return fmt.Sprintf("%s: expected [%s] but it was not extracted", ad.AttributeName, ad.Expected)
+	}
+	return fmt.Sprintf("%s: expected [%s] but got [%s]", ad.AttributeName, ad.Expected, ad.Actual)
+}
+
+//Passed is true when the example was matched by its template and every expected attribute was extracted with the expected value
+func (er ExampleResult) Passed() bool {
+	return er.Err == nil && len(er.Diffs) == 0
+}
+
+//Failed is the number of examples that did not pass
+func (er ExampleReport) Failed() int {
+	failed := 0
+
+	for _, result := range er.Results {
+		if !result.Passed() {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+//Passed is true when every example passed
+func (er ExampleReport) Passed() bool {
+	return er.Failed() == 0
+}
+
+//RunExamples parses the examples declared by each template the way ParseText does and compares the extracted attributes with the
+//expected ones. Only the attributes extracted by the template the example belongs to are compared, so other templates matching the
+//same document do not change the outcome. An attribute extracted more than once has to have the expected value every time.
+//Relative example file paths are resolved against baseDir, usually the directory the config was loaded from.
+func (t *Templates) RunExamples(baseDir string) ExampleReport {
+	templateMap := map[string]template(*t)
+	names := make([]string, 0, len(templateMap))
+
+	for name := range templateMap {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	report := ExampleReport{Results: make([]ExampleResult, 0)}
+
+	for _, name := range names {
+		for index, example := range templateMap[name].Examples {
+			report.Results = append(report.Results, t.runExample(templateMap[name], index, example, baseDir))
+		}
+	}
+
+	return report
+}
+
+func (t *Templates) runExample(exampleTemplate template, index int, example templateExample, baseDir string) ExampleResult {
+	result := ExampleResult{TemplateName: exampleTemplate.Name, Example: fmt.Sprintf("example %d", index+1), Diffs: make([]AttributeDiff, 0)}
+	text := example.Text
+
+	if example.File != "" {
+		result.Example = example.File
+		fileName := example.File
+
+		if !filepath.IsAbs(fileName) {
+			fileName = filepath.Join(baseDir, fileName)
+		}
+
+		fileContent, err := os.ReadFile(fileName)
+
+		if err != nil {
+			result.Err = fmt.Errorf("ERROR: Could not read example file %s. Error is %s", fileName, err.Error())
+			return result
+		}
+
+		text = string(fileContent)
+	}
+
+	exampleContent := content{OriginalText: text}
+	exampleContent.prepare()
+	keyValuePairs, failures, matched, err := exampleTemplate.parse(exampleContent)
+
+	if !matched {
+		result.Err = fmt.Errorf("ERROR: Template %s did not match its example", exampleTemplate.Name)
+	} else if err != nil {
+		result.Err = err
+	} else if len(failures) > 0 {
+		result.Err = &AssertionError{Failures: failures}
+	}
+
+	values := map[string][]string{}
+	for _, keyValue := range keyValuePairs {
+		values[keyValue.AttributeName] = append(values[keyValue.AttributeName], keyValue.AttributeValue)
+	}
+
+	for _, expected := range example.Expected {
+		actualValues, found := values[expected.Name]
+
+		for _, actual := range actualValues {
+			if actual != expected.Value {
+				result.Diffs = append(result.Diffs, AttributeDiff{AttributeName: expected.Name, Expected: expected.Value, Actual: strings.Join(actualValues, ", "), Found: true})
+				break
+			}
+		}
+
+		if !found {
+			result.Diffs = append(result.Diffs, AttributeDiff{AttributeName: expected.Name, Expected: expected.Value})
+		}
+	}
+
+	return result
+}
+
+func buildExamples(templateDef []byte) ([]templateExample, error) {
+	examples := make([]templateExample, 0)
+	var parseError error
+
+	jsonparser.ArrayEach(templateDef, func(value []byte, dataType jsonparser.ValueType, offset int, err error) {
+		if parseError != nil {
+			return
+		}
+
+		example := templateExample{Expected: make([]expectedAttribute, 0)}
+		example.File, _ = jsonparser.GetString(value, "file")
+		example.Text, _ = jsonparser.GetString(value, "text")
+
+		if (example.File == "") == (example.Text == "") {
+			parseError = fmt.Errorf("ERROR: Example %d should have either a file or a text", len(examples)+1)
+			return
+		}
+
+		parseError = jsonparser.ObjectEach(value, func(key []byte, expectedValue []byte, dataType jsonparser.ValueType, offset int) error {
+			if dataType == jsonparser.String {
+				unescaped, err := jsonparser.ParseString(expectedValue)
+				if err != nil {
+					return err
+				}
+				expectedValue = []byte(unescaped)
+			}
+			example.Expected = append(example.Expected, expectedAttribute{Name: string(key), Value: string(expectedValue)})
+			return nil
+		}, "expected")
+
+		if parseError == jsonparser.KeyPathNotFoundError {
+			parseError = fmt.Errorf("ERROR: Example %d should list the expected attributes", len(examples)+1)
+		}
+
+		examples = append(examples, example)
+	}, "examples")
+
+	return examples, parseError
+}
@@ -0,0 +1,127 @@
+package osmosis
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func exampleConfig(examples string) string {
+	return `{"templates": [
+		{
+			"templateName": "Ola",
+			"matchers": {"matcherType": "oneWordMatcher", "words": "ANI Technologies"},
+			"sections": [{
+				"contentSelector": {"selectorType": "lineNumberSelector", "fromLine": 1, "toLine": 1},
+				"contentExtractors": [{"extractorType": "regexExtractor", "regex": "Invoice ID\s+(\w+)", "attributeName": "invoiceNumber", "groupNumber": 1}]
+			}],
+			"examples": ` + examples + `
+		}
+	]}`
+}
+
+func TestThatExamplesPassWhenExpectedAttributesAreExtracted(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "ola.txt"), []byte(contentString), 0644)
+
+	templates, err := LoadConfig(strings.NewReader(exampleConfig(`[
+		{"file": "ola.txt", "expected": {"invoiceNumber": "1IE88NHTQ55547"}},
+		{"text": "ANI Technologies Invoice ID CRN123", "expected": {"invoiceNumber": "CRN123"}}
+	]`)))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	report := templates.RunExamples(dir)
+
+	if len(report.Results) != 2 || !report.Passed() || report.Results[0].Example != "ola.txt" || report.Results[1].Example != "example 2" {
+		t.Errorf("Expected both examples to pass but got %v", report.Results)
+	}
+}
+
+func TestThatExamplesReportDiffsPerAttribute(t *testing.T) {
+	templates, _ := LoadConfig(strings.NewReader(exampleConfig(`[
+		{"text": "ANI Technologies Invoice ID CRN123", "expected": {"invoiceNumber": "CRN999", "customerName": "Jacob"}},
+		{"text": "Uber Invoice ID CRN123", "expected": {}},
+		{"file": "missing.txt", "expected": {}}
+	]`)))
+
+	report := templates.RunExamples(t.TempDir())
+
+	if report.Failed() != 3 {
+		t.Fatalf("Expected all examples to fail but got %v", report.Results)
+	}
+
+	diffs := report.Results[0].Diffs
+
+	if len(diffs) != 2 || diffs[0].String() != "invoiceNumber: expected [CRN999] but got [CRN123]" || diffs[1].String() != "customerName: expected [Jacob] but it was not extracted" {
+		t.Errorf("Expected a diff for each attribute that did not match but got %v", diffs)
+	}
+
+	if report.Results[1].Err == nil || !strings.Contains(report.Results[1].Err.Error(), "did not match") {
+		t.Errorf("Expected an example that the template does not match to fail but got %v", report.Results[1].Err)
+	}
+
+	if report.Results[2].Err == nil || !strings.Contains(report.Results[2].Err.Error(), "missing.txt") {
+		t.Errorf("Expected an example file that can not be read to fail but got %v", report.Results[2].Err)
+	}
+}
+
+func TestThatInvalidExamplesAreRejected(t *testing.T) {
+	invalidExamples := map[string]string{
+		"no document":            `[{"expected": {"invoiceNumber": "CRN123"}}]`,
+		"file and text":          `[{"file": "ola.txt", "text": "ANI", "expected": {}}]`,
+		"no expected attributes": `[{"text": "ANI Technologies"}]`,
+	}
+
+	for description, examples := range invalidExamples {
+		if _, err := LoadConfig(strings.NewReader(exampleConfig(examples))); err == nil {
+			t.Errorf("Expected an error for an example with %s", description)
+		}
+	}
+}
+
+func TestThatExamplesOnlyCompareAttributesOfTheirOwnTemplate(t *testing.T) {
+	config := `{"templates": [
+		{
+			"templateName": "Ola",
+			"matchers": {"matcherType": "oneWordMatcher", "words": "ANI Technologies"},
+			"sections": [{
+				"contentSelector": {"selectorType": "lineNumberSelector", "fromLine": 1, "toLine": 2},
+				"contentExtractors": [{"extractorType": "regexExtractor", "regex": "Invoice ID\s+(\w+)", "attributeName": "invoiceNumber", "groupNumber": 1}]
+			}],
+			"examples": [{"text": "ANI Technologies Invoice ID CRN123", "expected": {"invoiceNumber": "CRN123"}}]
+		},
+		{
+			"templateName": "Generic",
+			"matchers": {"matcherType": "oneWordMatcher", "words": "Invoice"},
+			"sections": [{
+				"contentSelector": {"selectorType": "regexSelector", "regex": "(\w+)", "all": true},
+				"contentExtractors": [{"extractorType": "regexExtractor", "regex": "(\w+)", "attributeName": "invoiceNumber", "groupNumber": 1}]
+			}],
+			"examples": [{"text": "Invoice ID CRN123", "expected": {"invoiceNumber": "CRN123"}}]
+		}
+	]}`
+
+	templates, err := LoadConfig(strings.NewReader(config))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		report := templates.RunExamples("")
+
+		if len(report.Results) != 2 || !report.Results[1].Passed() {
+			t.Fatalf("Expected the Ola example to pass even though the Generic template matches it too but got %v", report.Results)
+		}
+
+		diffs := report.Results[0].Diffs
+
+		if len(diffs) != 1 || diffs[0].String() != "invoiceNumber: expected [CRN123] but got [Invoice, ID, CRN123]" {
+			t.Fatalf("Expected every value of a repeated attribute to be reported but got %v", diffs)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package osmosis
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+//TemplateStore holds templates loaded from config files and directories and reloads them when the files change.
+//A reload only replaces the templates when the whole config loads successfully, otherwise the previous templates stay in use.
+//ParseText and Templates can be called from many goroutines, also while a reload is in progress.
+type TemplateStore struct {
+	paths       []string
+	variables   map[string]string
+	reloadMutex sync.Mutex
+	mutex       sync.RWMutex
+	templates   Templates
+	fileStates  map[string]fileState
+	loadedFiles []string
+	lastError   error
+	events      chan ReloadEvent
+	stop        chan struct{}
+	stopOnce    sync.Once
+}
+
+//ReloadEvent is sent on the events channel of a TemplateStore after every reload attempt.
+//Err is nil when the new templates were loaded and swapped in, otherwise it explains why the previous templates were kept.
+type ReloadEvent struct {
+	Time          time.Time
+	TemplateCount int
+	Err           error
+}
+
+type fileState struct {
+	ModTime time.Time
+	Size    int64
+}
+
+//NewTemplateStore loads the templates from the config files and directories. Directories are searched recursively for JSON, YAML
+//and TOML config files. An error is returned when the initial load fails.
+func NewTemplateStore(paths ...string) (*TemplateStore, error) {
+	return NewTemplateStoreWithVariables(nil, paths...)
+}
+
+//NewTemplateStoreWithVariables loads the templates like NewTemplateStore. Values in the variables map take precedence over
+//environment variables and the variables blocks of the files when ${NAME} is replaced, also when the templates are reloaded.
+func NewTemplateStoreWithVariables(variables map[string]string, paths ...string) (*TemplateStore, error) {
+	store := &TemplateStore{
+		paths:     paths,
+		variables: variables,
+		events:    make(chan ReloadEvent, 16),
+		stop:      make(chan struct{}),
+	}
+
+	if err := store.Reload(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+//Templates returns the templates currently in use
+func (ts *TemplateStore) Templates() Templates {
+	ts.mutex.RLock()
+	defer ts.mutex.RUnlock()
+
+	return ts.templates
+}
+
+//ParseText parses the content using the templates currently in use, see Templates.ParseText
+func (ts *TemplateStore) ParseText(docReader io.Reader) ([]ExtractedContent, error) {
+	templates := ts.Templates()
+	return templates.ParseText(docReader)
+}
+
+//LastError returns the error of the last reload, or nil when it succeeded
+func (ts *TemplateStore) LastError() error {
+	ts.mutex.RLock()
+	defer ts.mutex.RUnlock()
+
+	return ts.lastError
+}
+
+//Events returns the channel on which a ReloadEvent is sent after every reload. Events are dropped when nobody reads them.
+func (ts *TemplateStore) Events() <-chan ReloadEvent {
+	return ts.events
+}
+
+//Reload loads the config files again and swaps in the new templates if they load successfully
+func (ts *TemplateStore) Reload() error {
+	ts.reloadMutex.Lock()
+	defer ts.reloadMutex.Unlock()
+
+	ts.mutex.RLock()
+	fileStates := fileStatesOf(ts.paths, ts.loadedFiles)
+	ts.mutex.RUnlock()
+
+	configFiles, err := expandConfigPaths(ts.paths)
+	loader := newFileConfigLoader()
+	loader.Variables = ts.variables
+	var templates Templates
+
+	if err == nil {
+		templates, err = loader.loadAll(configFiles)
+	}
+
+	loadedFiles := make([]string, 0, len(loader.Loaded))
+	for loadedFile := range loader.Loaded {
+		loadedFiles = append(loadedFiles, loadedFile)
+	}
+
+	for name, state := range fileStatesOf(nil, loadedFiles) {
+		if _, found := fileStates[name]; !found {
+			fileStates[name] = state
+		}
+	}
+
+	ts.mutex.Lock()
+	ts.lastError = err
+	ts.loadedFiles = loadedFiles
+	ts.fileStates = fileStates
+	if err == nil {
+		ts.templates = templates
+	}
+	ts.mutex.Unlock()
+
+	select {
+	case ts.events <- ReloadEvent{Time: time.Now(), TemplateCount: len(templates), Err: err}:
+	default:
+	}
+
+	return err
+}
+
+//Watch polls the config files for changes at the interval and reloads the templates when a file is added, removed or modified.
+//It returns immediately, the polling stops when the store is closed.
+func (ts *TemplateStore) Watch(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ts.stop:
+				return
+			case <-ticker.C:
+				if ts.changed() {
+					ts.Reload()
+				}
+			}
+		}
+	}()
+}
+
+//Close stops watching the config files
+func (ts *TemplateStore) Close() {
+	ts.stopOnce.Do(func() {
+		close(ts.stop)
+	})
+}
+
+func (ts *TemplateStore) changed() bool {
+	ts.mutex.RLock()
+	defer ts.mutex.RUnlock()
+
+	currentStates := fileStatesOf(ts.paths, ts.loadedFiles)
+
+	if len(currentStates) != len(ts.fileStates) {
+		return true
+	}
+
+	for name, state := range currentStates {
+		if previousState, found := ts.fileStates[name]; !found || !previousState.ModTime.Equal(state.ModTime) || previousState.Size != state.Size {
+			return true
+		}
+	}
+
+	return false
+}
+
+//fileStatesOf covers the config files found in the paths and the files loaded from them, including the files they include
+func fileStatesOf(paths []string, loadedFiles []string) map[string]fileState {
+	states := map[string]fileState{}
+	configFiles, _ := expandConfigPaths(paths)
+
+	for _, name := range append(configFiles, loadedFiles...) {
+		if info, err := os.Stat(name); err == nil {
+			states[name] = fileState{ModTime: info.ModTime(), Size: info.Size()}
+		}
+	}
+
+	return states
+}
+
+//expandConfigPaths replaces every directory in paths with the config files in it and its subdirectories
+func expandConfigPaths(paths []string) ([]string, error) {
+	configFiles := make([]string, 0)
+
+	for _, configPath := range paths {
+		info, err := os.Stat(configPath)
+
+		if err != nil {
+			return nil, fmt.Errorf("ERROR: Could not read config path %s. Error is %s", configPath, err.Error())
+		}
+
+		if !info.IsDir() {
+			configFiles = append(configFiles, configPath)
+			continue
+		}
+
+		dirFiles := make([]string, 0)
+		err = filepath.WalkDir(configPath, func(name string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && isConfigFile(name) {
+				dirFiles = append(dirFiles, name)
+			}
+			return nil
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("ERROR: Could not read config directory %s. Error is %s", configPath, err.Error())
+		}
+
+		sort.Strings(dirFiles)
+		configFiles = append(configFiles, dirFiles...)
+	}
+
+	return configFiles, nil
+}
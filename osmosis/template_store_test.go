@@ -0,0 +1,120 @@
+package osmosis
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func storeTemplateConfig(templateName string, words string) string {
+	return `{"templates": [{"templateName": "` + templateName + `", "matchers": {"matcherType": "oneWordMatcher", "words": "` + words + `"},
+		"sections": [{"contentSelector": {"selectorType": "lineNumberSelector", "fromLine": 1, "toLine": 1},
+		"contentExtractors": [{"extractorType": "regexExtractor", "regex": "Invoice ID\s+(\w+)", "attributeName": "invoiceNumber", "groupNumber": 1}]}]}]}`
+}
+
+func writeStoreConfig(t *testing.T, name string, config string, modTime time.Time) {
+	if err := os.WriteFile(name, []byte(config), 0644); err != nil {
+		t.Fatalf("Could not write config. Error is %s", err.Error())
+	}
+
+	os.Chtimes(name, modTime, modTime)
+}
+
+func waitForReload(t *testing.T, store *TemplateStore) ReloadEvent {
+	select {
+	case event := <-store.Events():
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected the store to reload")
+	}
+
+	return ReloadEvent{}
+}
+
+func TestThatTemplateStoreReloadsChangedConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "vendors.json")
+	writeStoreConfig(t, configFile, storeTemplateConfig("Uber", "Uber"), time.Now().Add(-time.Hour))
+
+	store, err := NewTemplateStore(dir)
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	defer store.Close()
+	<-store.Events()
+
+	if keyValuePairs, _ := store.ParseText(strings.NewReader(contentString)); len(keyValuePairs) != 0 {
+		t.Fatalf("Did not expect the Uber template to match the Ola invoice")
+	}
+
+	store.Watch(10 * time.Millisecond)
+	writeStoreConfig(t, configFile, storeTemplateConfig("Ola", "ANI Technologies"), time.Now())
+
+	if event := waitForReload(t, store); event.Err != nil || event.TemplateCount != 1 {
+		t.Fatalf("Expected a successful reload but got %v", event)
+	}
+
+	keyValuePairs, _ := store.ParseText(strings.NewReader(contentString))
+
+	if len(keyValuePairs) != 1 || keyValuePairs[0].AttributeValue != "1IE88NHTQ55547" {
+		t.Errorf("Expected the reloaded Ola template to be used but got %v", keyValuePairs)
+	}
+}
+
+func TestThatTemplateStoreKeepsPreviousTemplatesWhenReloadFails(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "vendors.json")
+	writeStoreConfig(t, configFile, storeTemplateConfig("Ola", "ANI Technologies"), time.Now().Add(-time.Hour))
+
+	store, _ := NewTemplateStore(configFile)
+	defer store.Close()
+	<-store.Events()
+
+	store.Watch(10 * time.Millisecond)
+	writeStoreConfig(t, configFile, `{"templates": [{"templateName": "Broken"}]}`, time.Now())
+
+	if event := waitForReload(t, store); event.Err == nil {
+		t.Fatalf("Expected the reload of a broken config to fail")
+	}
+
+	if store.LastError() == nil || len(store.Templates()) != 1 || store.Templates()["Ola"].Name != "Ola" {
+		t.Errorf("Expected the previous templates to stay in use")
+	}
+}
+
+func TestThatParseTextIsSafeDuringReloads(t *testing.T) {
+	dir := t.TempDir()
+	writeStoreConfig(t, filepath.Join(dir, "ola.json"), storeTemplateConfig("Ola", "ANI Technologies"), time.Now())
+
+	store, _ := NewTemplateStore(dir)
+	var waitGroup sync.WaitGroup
+
+	for worker := 0; worker < 4; worker++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for attempt := 0; attempt < 20; attempt++ {
+				if keyValuePairs, err := store.ParseText(strings.NewReader(contentString)); err != nil || len(keyValuePairs) != 1 {
+					t.Errorf("Expected every parse to use a complete set of templates but got %v and %v", keyValuePairs, err)
+				}
+			}
+		}()
+	}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		store.Reload()
+	}
+
+	waitGroup.Wait()
+}
+
+func TestThatTemplateStoreReturnsErrorWhenInitialLoadFails(t *testing.T) {
+	if _, err := NewTemplateStore(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("Expected an error for a missing config file")
+	}
+}
@@ -0,0 +1,200 @@
+package osmosis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/buger/jsonparser"
+)
+
+//variableRegex matches ${NAME} and ${NAME:-default}. $${ is an escaped, literal ${.
+var variableRegex = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_.]*)(:-([^}]*))?\}`)
+
+//variableResolver looks up a variable in the caller supplied values first, then in the environment and finally in the
+//variables block of the config. Values in the variables block can refer to other variables.
+//Ambiguous holds the errors for variables that can not be used because config files loaded together define them differently.
+type variableResolver struct {
+	Supplied  map[string]string
+	Defined   map[string]string
+	Ambiguous map[string]error
+	LookupEnv func(name string) (string, bool)
+	Resolving map[string]bool
+}
+
+func newVariableResolver(supplied map[string]string, defined map[string]string) variableResolver {
+	return variableResolver{Supplied: supplied, Defined: defined, Ambiguous: map[string]error{}, LookupEnv: os.LookupEnv, Resolving: map[string]bool{}}
+}
+
+//resolveVariables returns the config with every ${NAME} in it replaced by the value of the variable
+func resolveVariables(config []byte, supplied map[string]string) ([]byte, error) {
+	return newVariableResolver(supplied, configVariables(config)).resolve(config)
+}
+
+//configVariables returns the entries of the variables block of the config
+func configVariables(config []byte) map[string]string {
+	variables := map[string]string{}
+
+	jsonparser.ObjectEach(config, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+		if dataType == jsonparser.String {
+			if unescaped, err := jsonparser.ParseString(value); err == nil {
+				value = []byte(unescaped)
+			}
+		}
+		variables[string(key)] = string(value)
+		return nil
+	}, "variables")
+
+	return variables
+}
+
+func (vr variableResolver) resolve(config []byte) ([]byte, error) {
+	if !bytes.Contains(config, []byte("${")) {
+		return config, nil
+	}
+
+	return vr.substituteValue(config, jsonparser.Object, "")
+}
+
+//substituteValue replaces the variables in the strings of a config value. Values written into regex fields keep their backslashes,
+//the way regexes are written in the DSL, while values written into other strings are escaped as JSON.
+func (vr variableResolver) substituteValue(value []byte, dataType jsonparser.ValueType, key string) ([]byte, error) {
+	var substituteError error
+
+	switch dataType {
+	case jsonparser.Array:
+		items := make([][]byte, 0)
+
+		jsonparser.ArrayEach(value, func(item []byte, itemType jsonparser.ValueType, offset int, err error) {
+			if substituteError == nil {
+				item, substituteError = vr.substituteValue(item, itemType, key)
+				items = append(items, item)
+			}
+		})
+
+		return append(append([]byte("["), bytes.Join(items, []byte(","))...), ']'), substituteError
+	case jsonparser.Object:
+		entries := make([][]byte, 0)
+
+		substituteError = jsonparser.ObjectEach(value, func(entryKey []byte, entry []byte, entryType jsonparser.ValueType, offset int) error {
+			var err error
+			if string(entryKey) != "variables" || key != "" {
+				entry, err = vr.substituteValue(entry, entryType, string(entryKey))
+			} else if entryType == jsonparser.String {
+				entry = []byte(`"` + string(entry) + `"`)
+			}
+			entries = append(entries, append([]byte(`"`+string(entryKey)+`":`), entry...))
+			return err
+		})
+
+		return append(append([]byte("{"), bytes.Join(entries, []byte(","))...), '}'), substituteError
+	case jsonparser.String:
+		if !bytes.Contains(value, []byte("${")) {
+			return []byte(`"` + string(value) + `"`), nil
+		}
+
+		if rawStringKeys[key] {
+			substituted, err := vr.substitute(value, "config", rawStringReplacer.Replace)
+			return []byte(`"` + string(substituted) + `"`), err
+		}
+
+		unescaped, err := jsonparser.ParseString(value)
+
+		if err != nil {
+			return nil, fmt.Errorf("ERROR: Could not read %s to replace variables in it. Error is %s", key, err.Error())
+		}
+
+		substituted, err := vr.substitute([]byte(unescaped), "config", nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		encoded, _ := json.Marshal(string(substituted))
+		return encoded, nil
+	}
+
+	return value, nil
+}
+
+//substitute replaces the variables in text. The values of the variables are passed through escape, when it is set.
+func (vr variableResolver) substitute(text []byte, source string, escape func(value string) string) ([]byte, error) {
+	var substituteError error
+	unknownNames := map[string]bool{}
+
+	substituted := variableRegex.ReplaceAllFunc(text, func(match []byte) []byte {
+		if bytes.HasPrefix(match, []byte("$$")) {
+			return match[1:]
+		}
+
+		groups := variableRegex.FindSubmatch(match)
+		value, found, err := vr.lookup(string(groups[1]))
+
+		if err != nil && substituteError == nil {
+			substituteError = err
+		}
+
+		if !found {
+			if len(groups[2]) == 0 {
+				unknownNames[string(groups[1])] = true
+				return match
+			}
+			value = string(groups[3])
+		}
+
+		if escape != nil {
+			value = escape(value)
+		}
+		return []byte(value)
+	})
+
+	if substituteError != nil {
+		return nil, substituteError
+	}
+
+	if len(unknownNames) > 0 {
+		names := make([]string, 0, len(unknownNames))
+		for name := range unknownNames {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("ERROR: Variables %s used in %s are not defined", strings.Join(names, ", "), source)
+	}
+
+	return substituted, nil
+}
+
+func (vr variableResolver) lookup(name string) (string, bool, error) {
+	if value, found := vr.Supplied[name]; found {
+		return value, true, nil
+	}
+
+	if value, found := vr.LookupEnv(name); found {
+		return value, true, nil
+	}
+
+	value, found := vr.Defined[name]
+
+	if !found {
+		return "", false, vr.Ambiguous[name]
+	}
+
+	if vr.Resolving[name] {
+		return "", false, fmt.Errorf("ERROR: Variable %s refers to itself", name)
+	}
+
+	vr.Resolving[name] = true
+	defer delete(vr.Resolving, name)
+
+	resolved, err := vr.substitute([]byte(value), "variable "+name, nil)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	return string(resolved), true, nil
+}
@@ -0,0 +1,112 @@
+package osmosis
+
+import (
+	"strings"
+	"testing"
+)
+
+var variablesConfig = `{
+	"variables": {
+		"COMPANY": "ANI Technologies",
+		"CURRENCY": "₹",
+		"INVOICE_LABEL": "Invoice ID"
+	},
+	"templates": [
+		{
+			"templateName": "Ola",
+			"matchers": {"matcherType": "oneWordMatcher", "words": "${COMPANY}"},
+			"sections": [
+				{
+					"contentSelector": {"selectorType": "lineNumberSelector", "fromLine": 1, "toLine": 1},
+					"contentExtractors": [
+						{"extractorType": "regexExtractor", "regex": "${INVOICE_LABEL}\s+(\w+)", "attributeName": "invoiceNumber", "groupNumber": 1},
+						{"extractorType": "regexExtractor", "regex": "(Domlur)", "attributeName": "currency", "defaultValue": "${CURRENCY}", "groupNumber": 2},
+						{"extractorType": "regexExtractor", "regex": "(Domlur)", "attributeName": "region", "defaultValue": "${REGION:-south}", "groupNumber": 2}
+					]
+				}
+			]
+		}
+	]
+}`
+
+func TestThatVariablesAreReplacedBeforeTemplatesAreBuilt(t *testing.T) {
+	templates, err := LoadConfig(strings.NewReader(variablesConfig))
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, _ := templates.ParseText(strings.NewReader(contentString))
+	values := attributeValues(keyValuePairs)
+
+	if values["invoiceNumber"] != "1IE88NHTQ55547" || values["currency"] != "₹" || values["region"] != "south" {
+		t.Errorf("Expected variables from the config to be used but got %v", values)
+	}
+}
+
+func TestThatSuppliedVariablesTakePrecedenceOverEnvironmentAndConfig(t *testing.T) {
+	t.Setenv("CURRENCY", "$")
+	t.Setenv("REGION", "west")
+
+	templates, err := LoadConfigWithVariables(strings.NewReader(variablesConfig), map[string]string{"REGION": "north"})
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, _ := templates.ParseText(strings.NewReader(contentString))
+	values := attributeValues(keyValuePairs)
+
+	if values["currency"] != "$" || values["region"] != "north" {
+		t.Errorf("Expected the environment to override the config and supplied values to override both but got %v", values)
+	}
+}
+
+func TestThatVariablesCanReferToOtherVariables(t *testing.T) {
+	resolved, err := resolveVariables([]byte(`{"variables": {"A": "${B}-x", "B": "say \"hi\""}, "value": "${A}", "literal": "$${A}"}`), nil)
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	if !strings.Contains(string(resolved), `"value":"say \"hi\"-x"`) || !strings.Contains(string(resolved), `"literal":"${A}"`) {
+		t.Errorf("Expected nested variables to be resolved and escaped ones to be kept but got %s", string(resolved))
+	}
+}
+
+func TestThatVariableValuesAreEscapedForTheFieldTheyAreUsedIn(t *testing.T) {
+	config := `{"templates": [{"templateName": "Ola", "matchers": {"matcherType": "oneWordMatcher", "words": "ANI Technologies"},
+		"sections": [{"contentSelector": {"selectorType": "lineNumberSelector", "fromLine": 1, "toLine": 1},
+		"contentExtractors": [
+			{"extractorType": "regexExtractor", "regex": "${LABEL}\s+(\w+)", "attributeName": "invoiceNumber", "groupNumber": 1},
+			{"extractorType": "regexExtractor", "regex": "(Domlur)", "attributeName": "folder", "defaultValue": "${FOLDER}", "groupNumber": 2},
+			{"extractorType": "regexExtractor", "regex": "(Domlur)", "attributeName": "discount", "defaultValue": "${DISCOUNT}", "groupNumber": 2}
+		]}]}]}`
+
+	templates, err := LoadConfigWithVariables(strings.NewReader(config), map[string]string{"LABEL": `Invoice\s+ID`, "FOLDER": `C:\temp`, "DISCOUNT": "50\\%\n"})
+
+	if err != nil {
+		t.Fatalf("Did not expect error to be returned. But was %s", err.Error())
+	}
+
+	keyValuePairs, _ := templates.ParseText(strings.NewReader(contentString))
+	values := attributeValues(keyValuePairs)
+
+	if values["invoiceNumber"] != "1IE88NHTQ55547" || values["folder"] != `C:\temp` || values["discount"] != "50\\%\n" {
+		t.Errorf("Expected backslashes to be kept in regexes and in other values but got %v", values)
+	}
+}
+
+func TestThatUndefinedAndCyclicVariablesAreReported(t *testing.T) {
+	_, err := LoadConfig(strings.NewReader(`{"templates": [{"templateName": "${OSMOSIS_UNDEFINED_VARIABLE}"}]}`))
+
+	if err == nil || !strings.Contains(err.Error(), "OSMOSIS_UNDEFINED_VARIABLE") {
+		t.Errorf("Expected an error naming the undefined variable but got %v", err)
+	}
+
+	_, err = resolveVariables([]byte(`{"variables": {"A": "${B}", "B": "${A}"}, "value": "${A}"}`), nil)
+
+	if err == nil || !strings.Contains(err.Error(), "refers to itself") {
+		t.Errorf("Expected an error for variables that refer to each other but got %v", err)
+	}
+}